@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"errors"
+)
+
+// Ambiguous() wraps err to say the outcome of the operation is unknown,
+// e.g. a write that timed out may or may not have been applied by the server.
+// It is not retryable in IsRetryable(), RetryableAt() and ShouldRetry(), even when err is,
+// unless the operation is declared idempotent with Idempotent().
+func Ambiguous(err error) AmbiguousError {
+	if err == nil {
+		return nil
+	}
+	return ambiguousError{
+		baseError: baseError{
+			wrapped: err,
+			source:  GetCaller(2),
+		},
+	}
+}
+
+// IsAmbiguous() returns true when err has an error made with Ambiguous()
+func IsAmbiguous(err error) bool {
+	var ae interface{ Ambiguous() bool }
+	return errors.As(err, &ae) && ae.Ambiguous()
+}
+
+// Idempotent() wraps err to say the operation can be repeated safely,
+// so an ambiguous outcome (see Ambiguous()) can be retried like other errors.
+// It only applies to the ambiguous errors that it wraps, like Idempotent(Ambiguous(err)),
+// not to an Ambiguous() wrapped around it or joined with it.
+func Idempotent(err error) BaseError {
+	if err == nil {
+		return nil
+	}
+	return idempotentError{
+		baseError: baseError{
+			wrapped: err,
+			source:  GetCaller(2),
+		},
+	}
+}
+
+// IsIdempotent() returns true when err has an error made with Idempotent()
+func IsIdempotent(err error) bool {
+	var ie interface{ Idempotent() bool }
+	return errors.As(err, &ie) && ie.Idempotent()
+}
+
+// ambiguousNotIdempotent returns true when err has an Ambiguous() error
+// that is not wrapped by Idempotent() on its path from err
+func ambiguousNotIdempotent(err error, idempotent bool) bool {
+	for err != nil {
+		if ie, ok := err.(interface{ Idempotent() bool }); ok && ie.Idempotent() {
+			idempotent = true
+		}
+		if ae, ok := err.(interface{ Ambiguous() bool }); ok && ae.Ambiguous() && !idempotent {
+			return true
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, item := range e.Unwrap() {
+				if ambiguousNotIdempotent(item, idempotent) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+} //ambiguousNotIdempotent()
+
+type AmbiguousError interface {
+	BaseError
+	Ambiguous() bool
+}
+
+var _ AmbiguousError = (*ambiguousError)(nil)
+
+type ambiguousError struct {
+	baseError
+}
+
+func (err ambiguousError) Ambiguous() bool {
+	return true
+}
+
+func (err ambiguousError) String() string {
+	return "ambiguous"
+}
+
+type idempotentError struct {
+	baseError
+}
+
+func (err idempotentError) Idempotent() bool {
+	return true
+}
+
+func (err idempotentError) String() string {
+	return "idempotent"
+}
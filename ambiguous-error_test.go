@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmbiguous(t *testing.T) {
+	timeout := Retry(Wrap(&net.DNSError{Err: "i/o timeout", IsTimeout: true}, "cannot write"), time.Second)
+	err := Wrap(Ambiguous(timeout), "cannot send message")
+	assert.Equal(t, "cannot send message because cannot write because lookup : i/o timeout", err.Error())
+	assert.True(t, IsAmbiguous(err))
+	assert.False(t, IsIdempotent(err))
+	assert.False(t, IsRetryable(err))
+	_, ok := RetryableAt(err)
+	assert.False(t, ok)
+	_, ok = ShouldRetry(err)
+	assert.False(t, ok)
+	assert.False(t, IsPermanent(err))
+	assert.True(t, Is(err, timeout))
+	assert.Nil(t, Ambiguous(nil))
+	assert.Nil(t, Idempotent(nil))
+
+	//even when a layer outside wants to retry
+	assert.False(t, IsRetryable(Retry(err, time.Second)))
+
+	//not retried when only classified as transient
+	_, ok = ShouldRetry(Ambiguous(&net.DNSError{Err: "i/o timeout", IsTimeout: true}))
+	assert.False(t, ok)
+
+	//idempotent operations are retried
+	idempotent := Idempotent(err)
+	assert.True(t, IsIdempotent(idempotent))
+	assert.True(t, IsRetryable(idempotent))
+	at, ok := ShouldRetry(idempotent)
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), at, 100*time.Millisecond)
+	_, ok = ShouldRetry(Idempotent(Ambiguous(&net.DNSError{Err: "i/o timeout", IsTimeout: true})))
+	assert.True(t, ok)
+	assert.False(t, IsRetryable(Idempotent(Ambiguous(Error("not transient")))))
+
+	//only when wrapping the ambiguous error
+	_, ok = ShouldRetry(Ambiguous(Idempotent(timeout)))
+	assert.False(t, ok, "inner idempotent")
+	_, ok = ShouldRetry(Join(Idempotent(timeout), Ambiguous(timeout)))
+	assert.False(t, ok, "joined idempotent")
+	_, ok = ShouldRetry(Join(Idempotent(Ambiguous(timeout)), timeout))
+	assert.True(t, ok)
+
+	frames := Frames(idempotent)
+	assert.True(t, frames[0].Idempotent)
+	assert.True(t, frames[1].Ambiguous)
+	assert.NotEqual(t, Fingerprint(timeout), Fingerprint(Ambiguous(timeout)))
+}
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"io"
 )
 
 // all errors types defined in this package embeds this to offer BaseError interface
@@ -40,34 +41,16 @@ func (err baseError) Source() Caller {
 	return err.source
 }
 
-// implement fmt.Formatter
-// func (err baseError) Format(f fmt.State, c rune) {
-// var s string
-// s = fmt.Sprintf("base(%p)", err)
-// switch c {
-// case 'v':
-// 	s += fmt.Sprintf("%v:%s", err.source, err.Error())
-// default: //also for 's'
-// 	s += "...baseNoString..." //err.String()
-// }
-// io.WriteString(f, s)
-
-// if err.wrapped != nil {
-// 	stack := false
-// 	if f.Flag('+') {
-// 		stack = true
-// 		io.WriteString(f, " because ")
-// 	}
-// 	if f.Flag('-') {
-// 		stack = true
-// 		io.WriteString(f, "\n")
-// 	}
-// 	if stack {
-// 		if formatter, ok := err.wrapped.(fmt.Formatter); ok {
-// 			formatter.Format(f, c)
-// 		} else {
-// 			io.WriteString(f, err.wrapped.Error())
-// 		}
-// 	}
-// }
-// }
+// implement fmt.Formatter for error types that only add information to the wrapped error,
+// like a code or retry time, so the wrapped error is formatted as if it was not wrapped
+func (err baseError) Format(f fmt.State, c rune) {
+	if c == 'u' {
+		io.WriteString(f, userMessage(err, userLink(f)))
+		return
+	}
+	if formatter, ok := err.wrapped.(fmt.Formatter); ok {
+		formatter.Format(f, c)
+		return
+	}
+	io.WriteString(f, err.Error())
+}
@@ -0,0 +1,282 @@
+// errfmt reads log lines with error chains on stdin and prints each chain as an indented tree.
+//
+// It understands chains formatted with "%+v" or "%+V", like
+//
+//	HTTP POST /add: decode.go(50):invalid request because users.go(16):missing name
+//
+// and JSON arrays of errors.Frames(), on their own or in the "error" or "err" field of a JSON log line.
+// Other lines are printed as is.
+//
+// Usage:
+//
+//	errfmt [-root <module dir>] [-clickable] < app.log
+//
+// With -root, source references are resolved to files in the module, and with -clickable
+// they are printed as path:line which most editors and terminals can open.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+func main() {
+	rootFlag := flag.String("root", "", "module root directory, to resolve source references to local files")
+	clickableFlag := flag.Bool("clickable", false, "print source references as path:line")
+	flag.Parse()
+
+	p := printer{clickable: *clickableFlag}
+	if *rootFlag != "" {
+		var err error
+		if p.resolver, err = newResolver(*rootFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := p.copy(os.Stdout, os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+type printer struct {
+	resolver  *resolver
+	clickable bool
+}
+
+func (p printer) copy(w io.Writer, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) //error chains make long lines
+	for scanner.Scan() {
+		io.WriteString(w, p.format(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "cannot read input")
+	}
+	return nil
+}
+
+// format returns the line with the error chain in it as an indented tree,
+// or the line as is when it has no error chain
+func (p printer) format(line string) string {
+	prefix, frames, ok := parseJSON(line)
+	if !ok {
+		prefix, frames, ok = parseText(line)
+	}
+	if !ok {
+		return line + "\n"
+	}
+	sb := strings.Builder{}
+	if prefix != "" {
+		sb.WriteString(prefix + "\n")
+	}
+	p.write(&sb, frames, 1, "")
+	return sb.String()
+}
+
+// write the frames, each wrapped frame indented one level deeper than the previous
+func (p printer) write(sb *strings.Builder, frames []errors.Frame, depth int, marker string) {
+	for _, frame := range frames {
+		indent := strings.Repeat("  ", depth-1)
+		if marker != "" {
+			indent = indent[:len(indent)-len(marker)] + marker
+			marker = ""
+		}
+		if frame.Message == "" && len(frame.Joined) > 0 {
+			//only a list of joined errors
+			for _, joined := range frame.Joined {
+				p.write(sb, joined, depth+1, "- ")
+			}
+			continue
+		}
+		sb.WriteString(indent + frame.Message)
+		if frame.Code != 0 {
+			sb.WriteString(" [code=" + strconv.Itoa(frame.Code) + "]")
+		}
+		if frame.Kind != "" {
+			sb.WriteString(" [kind=" + frame.Kind + "]")
+		}
+		if frame.RetryAt != nil {
+			sb.WriteString(" [retry at " + frame.RetryAt.Format(time.RFC3339) + "]")
+		}
+		if frame.Permanent {
+			sb.WriteString(" [permanent]")
+		}
+		if frame.Ambiguous {
+			sb.WriteString(" [ambiguous]")
+		}
+		if frame.Idempotent {
+			sb.WriteString(" [idempotent]")
+		}
+		if len(frame.Context) > 0 {
+			names := slices.Sorted(maps.Keys(frame.Context))
+			for i, name := range names {
+				names[i] = name + "=" + frame.Context[name]
+			}
+			sb.WriteString(" [" + strings.Join(names, " ") + "]")
+		}
+		if frame.Source != "" {
+			sb.WriteString("  " + p.source(frame.Source))
+		}
+		sb.WriteString("\n")
+		for _, joined := range frame.Joined {
+			p.write(sb, joined, depth+1, "- ")
+		}
+		depth++
+	}
+}
+
+// source returns the source reference like "file.go(12)", resolved to a local file when possible
+func (p printer) source(source string) string {
+	m := sourcePattern.FindStringSubmatch(source)
+	if m == nil {
+		return source
+	}
+	file, line := m[1], m[2]
+	if p.resolver != nil {
+		if local, ok := p.resolver.resolve(file); ok {
+			file = local
+		}
+	}
+	if p.clickable {
+		return file + ":" + line
+	}
+	return file + "(" + line + ")"
+}
+
+var sourcePattern = regexp.MustCompile(`^(\S+\.go)\((\d+)\)$`)
+
+// markerPattern finds "file.go(12):" in front of messages formatted with %v or %V
+var markerPattern = regexp.MustCompile(`(\S+\.go\(\d+\)):`)
+
+// parseText parses a chain formatted with "%+v" or "%+V", returning the text before the first
+// source reference as prefix
+func parseText(line string) (prefix string, frames []errors.Frame, ok bool) {
+	loc := markerPattern.FindStringIndex(line)
+	if loc == nil {
+		return "", nil, false
+	}
+	prefix = strings.TrimSpace(line[:loc[0]])
+	for _, part := range strings.Split(line[loc[0]:], " because ") {
+		frame := errors.Frame{Message: part}
+		if m := markerPattern.FindStringSubmatchIndex(part); m != nil && m[0] == 0 {
+			frame.Source = part[m[2]:m[3]]
+			frame.Message = part[m[1]:]
+		}
+		frames = append(frames, frame)
+	}
+	return prefix, frames, true
+}
+
+// parseJSON parses a JSON array of frames, or a JSON object with frames in the "error" or "err" field
+func parseJSON(line string) (prefix string, frames []errors.Frame, ok bool) {
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, "["):
+		if err := json.Unmarshal([]byte(line), &frames); err != nil || len(frames) == 0 {
+			return "", nil, false
+		}
+		return "", frames, true
+	case strings.HasPrefix(line, "{"):
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			return "", nil, false
+		}
+		for _, name := range []string{"msg", "message"} {
+			if value, ok := fields[name]; ok {
+				json.Unmarshal(value, &prefix)
+				break
+			}
+		}
+		for _, name := range []string{"error", "err"} {
+			value, found := fields[name]
+			if !found {
+				continue
+			}
+			if json.Unmarshal(value, &frames) == nil && len(frames) > 0 {
+				return prefix, frames, true
+			}
+			var text string
+			if json.Unmarshal(value, &text) == nil {
+				if _, frames, ok := parseText(text); ok {
+					return prefix, frames, true
+				}
+			}
+		}
+	}
+	return "", nil, false
+} //parseJSON()
+
+// resolver finds local files for source references
+type resolver struct {
+	root      string
+	module    string              //module path from go.mod in root
+	basenames map[string][]string //local files by base name, for references without package
+}
+
+func newResolver(root string) (*resolver, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read go.mod in %s", root)
+	}
+	r := &resolver{
+		root:      root,
+		basenames: map[string][]string{},
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			r.module = strings.Trim(fields[1], `"`)
+			break
+		}
+	}
+	if r.module == "" {
+		return nil, errors.Errorf("no module in %s/go.mod", root)
+	}
+	err = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor" || d.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".go") {
+			r.basenames[d.Name()] = append(r.basenames[d.Name()], p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot list files in %s", root)
+	}
+	return r, nil
+} //newResolver()
+
+// resolve returns the local file for file from a source reference,
+// which is a package path with file name like Caller.PackageFile(), or only the file name
+func (r *resolver) resolve(file string) (string, bool) {
+	if strings.HasPrefix(file, r.module+"/") {
+		local := filepath.Join(r.root, filepath.FromSlash(strings.TrimPrefix(file, r.module+"/")))
+		if _, err := os.Stat(local); err == nil {
+			return local, true
+		}
+	}
+	//only the file name or a package outside the module, like "main/main.go",
+	//so use the file if there is only one with that name
+	if files := r.basenames[path.Base(file)]; len(files) == 1 {
+		return files[0], true
+	}
+	return "", false
+}
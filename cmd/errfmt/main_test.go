@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatText(t *testing.T) {
+	p := printer{}
+	assert.Equal(t, "not an error\n", p.format("not an error"))
+	assert.Equal(t,
+		"HTTP POST /add:\n"+
+			"invalid request  decode.go(50)\n"+
+			"  missing name  users.go(16)\n",
+		p.format("HTTP POST /add: decode.go(50):invalid request because users.go(16):missing name"))
+	assert.Equal(t,
+		"cannot load  github.com/my/app/config.go(12)\n"+
+			"  open /etc/app.conf: no such file or directory\n",
+		p.format("github.com/my/app/config.go(12):cannot load because open /etc/app.conf: no such file or directory"))
+}
+
+func TestFormatJSON(t *testing.T) {
+	at := time.Date(2025, 3, 1, 15, 3, 2, 0, time.UTC)
+	frames := []errors.Frame{
+		{Message: "invalid request", Source: "github.com/my/app/main.go(39)", Code: 400, Context: map[string]string{"tenant": "acme", "request_id": "req-1"}},
+		{Joined: [][]errors.Frame{
+			{{Message: "invalid address", Source: "users.go(42)"}, {Message: "missing street", Source: "users.go(59)"}},
+			{{Message: "busy", RetryAt: &at}},
+		}},
+	}
+	data, _ := json.Marshal(frames)
+	exp := "invalid request [code=400] [request_id=req-1 tenant=acme]  github.com/my/app/main.go(39)\n" +
+		"  - invalid address  users.go(42)\n" +
+		"      missing street  users.go(59)\n" +
+		"  - busy [retry at 2025-03-01T15:03:02Z]\n"
+	p := printer{}
+	assert.Equal(t, exp, p.format(string(data)))
+
+	//in a JSON log line
+	line, _ := json.Marshal(map[string]any{"level": "error", "msg": "request failed", "error": frames})
+	assert.Equal(t, "request failed\n"+exp, p.format(string(line)))
+	line, _ = json.Marshal(map[string]any{"msg": "request failed", "err": "main.go(39):invalid request because users.go(16):missing name"})
+	assert.Equal(t, "request failed\ninvalid request  main.go(39)\n  missing name  users.go(16)\n", p.format(string(line)))
+}
+
+func TestResolve(t *testing.T) {
+	root, _ := filepath.Abs("../..")
+	r, err := newResolver(root)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	p := printer{resolver: r, clickable: true}
+
+	err = errors.Wrap(errors.Error("missing name"), "invalid request")
+	s := p.format(fmt.Sprintf("%+V", err))
+	exp := filepath.Join(root, "cmd", "errfmt", "main_test.go")
+	assert.True(t, strings.Contains(s, "invalid request  "+exp+":"), s)
+
+	//basename only
+	assert.Equal(t, "cannot do it  "+filepath.Join(root, "frames.go")+":12\n", p.format("frames.go(12):cannot do it"))
+	//not found
+	assert.Equal(t, "cannot do it  nothere.go:12\n", p.format("nothere.go(12):cannot do it"))
+}
@@ -0,0 +1,25 @@
+// errorsvet reports misuse of github.com/go-msvc/errors, see packages errorsvet, errorstyle and validatorcover,
+// and format strings that do not match the args, with the printf analyzer of go vet.
+//
+// Run it on its own or with go vet:
+//
+//	errorsvet ./...
+//	go vet -vettool=$(which errorsvet) ./...
+package main
+
+import (
+	"github.com/go-msvc/errors/errorstyle"
+	"github.com/go-msvc/errors/errorsvet"
+	"github.com/go-msvc/errors/validatorcover"
+	"golang.org/x/tools/go/analysis/multichecker"
+	"golang.org/x/tools/go/analysis/passes/printf"
+)
+
+func main() {
+	multichecker.Main(
+		errorsvet.Analyzer,
+		errorstyle.Analyzer,
+		validatorcover.Analyzer,
+		printf.Analyzer,
+	)
+}
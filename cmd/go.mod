@@ -0,0 +1,20 @@
+module github.com/go-msvc/errors/cmd
+
+go 1.24.0
+
+require (
+	github.com/go-msvc/errors/errorstyle v0.1.0
+	github.com/go-msvc/errors/errorsvet v0.1.0
+	github.com/go-msvc/errors/validatorcover v0.1.0
+	github.com/go-msvc/errors/v2 v2.1.0
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/tools v0.42.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.33.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
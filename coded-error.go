@@ -2,7 +2,6 @@ package errors
 
 import (
 	"errors"
-	"fmt"
 	"strconv"
 )
 
@@ -20,10 +19,11 @@ func Code(err error, code int) CodedError {
 }
 
 func Codef(code int, format string, args ...interface{}) CodedError {
+	source := GetCaller(2)
 	return codedError{
 		baseError: baseError{
-			wrapped: fmt.Errorf(format, args...),
-			source:  GetCaller(2),
+			wrapped: formatted(source, nil, format, args...),
+			source:  source,
 		},
 		code: code,
 	}
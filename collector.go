@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"slices"
+	"sync"
+)
+
+// Collector collects errors from many calls, e.g. of a batch job processing millions of items,
+// without keeping all of them in memory, see NewCollector().
+// It is safe for concurrent use.
+type Collector struct {
+	max    int
+	mutex  sync.Mutex
+	errs   []error        //first error with each message
+	counts []int          //number of times each message was added
+	index  map[string]int //of each message in errs
+	more   int            //errors with other messages after max was reached
+	total  int
+}
+
+// NewCollector() returns a Collector that keeps up to max errors with different messages,
+// and counts the others
+func NewCollector(max int) *Collector {
+	return &Collector{
+		max:   max,
+		index: map[string]int{},
+	}
+}
+
+// Add() adds err to the collector, nil is ignored.
+// When an error with the same message was already added, only its count is incremented.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.total++
+	if i, ok := c.index[msg]; ok {
+		c.counts[i]++
+		return
+	}
+	if len(c.errs) >= c.max {
+		c.more++
+		return
+	}
+	c.index[msg] = len(c.errs)
+	c.errs = append(c.errs, err)
+	c.counts = append(c.counts, 1)
+}
+
+// Len() returns the number of errors added, including repeats and those not kept
+func (c *Collector) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.total
+}
+
+// Err() returns nil when no errors were added, else the errors joined, like Join(),
+// with the number of times each occurred and the number not kept, e.g.
+// "missing street (x37); invalid zip; and 9,812 more", where the count follows the whole error in all formats.
+// Is() and As() find the errors that were kept.
+func (c *Collector) Err() BaseError {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.errs) == 0 && c.more == 0 {
+		return nil
+	}
+	return joinedError{
+		baseError: baseError{
+			source: GetCaller(2),
+		},
+		errs:   slices.Clone(c.errs),
+		counts: slices.Clone(c.counts),
+		more:   c.more,
+	}
+}
@@ -0,0 +1,67 @@
+package errors_test
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/go-msvc/errors/v2/errtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	c := errors.NewCollector(2)
+	assert.Nil(t, c.Err())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Add(errors.Error("missing street"))
+				c.Add(nil)
+			}
+		}()
+	}
+	wg.Wait()
+	c.Add(errors.Wrap(os.ErrNotExist, "cannot open file"))
+	c.Add(errors.Wrap(os.ErrNotExist, "cannot open file"))
+	for i := 0; i < 9812; i++ {
+		c.Add(errors.Errorf("invalid zip %d", i))
+	}
+	c.Add(errors.Error("missing street"))
+	assert.Equal(t, 10000+2+9812+1, c.Len())
+
+	//the number of repeats is written after the whole error in all formats
+	err := c.Err()
+	assert.Equal(t, "missing street (x10001); cannot open file because file does not exist (x2); and 9,812 more", err.Error())
+	assert.Equal(t, "missing street (x10001); cannot open file because file does not exist (x2); and 9,812 more", fmt.Sprintf("%+s", err))
+	assert.Equal(t, "- missing street (x10001)\n- cannot open file\n  file does not exist (x2)\n- and 9,812 more", fmt.Sprintf("%-s", err))
+	errtest.Golden(t, err)
+	errtest.AssertIs(t, err, os.ErrNotExist)
+
+	//not changed by more errors
+	c.Add(errors.Error("other"))
+	assert.Equal(t, "missing street (x10001); cannot open file because file does not exist (x2); and 9,812 more", err.Error())
+	assert.Equal(t, "missing street (x10001); cannot open file because file does not exist (x2); and 9,813 more", c.Err().Error())
+}
+
+func TestCollectorNone(t *testing.T) {
+	c := errors.NewCollector(0)
+	for i := 0; i < 1234; i++ {
+		c.Add(errors.Errorf("error %d", i))
+	}
+	err := c.Err()
+	assert.Equal(t, "and 1,234 more", err.Error())
+	assert.Equal(t, "and 1,234 more", fmt.Sprintf("%+s", err))
+	assert.Equal(t, "- and 1,234 more", fmt.Sprintf("%-s", err))
+	assert.Equal(t, "- and 1,234 more", fmt.Sprintf("%-v", err))
+
+	c = errors.NewCollector(10)
+	c.Add(errors.Error("one"))
+	assert.Equal(t, "one", c.Err().Error())
+	errtest.AssertSourceHere(t, c.Err())
+}
@@ -0,0 +1,250 @@
+package errors
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrorCtx() is like Error() but also records values from ctx, like the request ID, see RegisterExtractor()
+func ErrorCtx(ctx context.Context, msg string) BaseError {
+	return &msgError{
+		baseError: baseError{
+			source: GetCaller(2),
+		},
+		msg:    msg,
+		values: contextValues(ctx),
+	}
+}
+
+// ErrorfCtx() is like Errorf() but also records values from ctx, like the request ID, see RegisterExtractor()
+func ErrorfCtx(ctx context.Context, format string, args ...interface{}) BaseError {
+	err := formatted(GetCaller(2), nil, format, args...)
+	err.values = contextValues(ctx)
+	return err
+}
+
+// WrapCtx() is like Wrap() but also records values from ctx, like the request ID, see RegisterExtractor()
+func WrapCtx(ctx context.Context, err error, msg string) BaseError {
+	if err == nil {
+		return nil
+	}
+	return &msgError{
+		baseError: baseError{
+			wrapped: err,
+			source:  GetCaller(2),
+		},
+		msg:    msg,
+		values: contextValues(ctx),
+	}
+}
+
+// WrapfCtx() is like Wrapf() but also records values from ctx, like the request ID, see RegisterExtractor()
+func WrapfCtx(ctx context.Context, err error, format string, args ...interface{}) BaseError {
+	if err == nil {
+		return nil
+	}
+	cerr := formatted(GetCaller(2), err, format, args...)
+	cerr.values = contextValues(ctx)
+	return cerr
+}
+
+// CodefCtx() is like Codef() but also records values from ctx, like the request ID, see RegisterExtractor()
+func CodefCtx(ctx context.Context, code int, format string, args ...interface{}) CodedError {
+	source := GetCaller(2)
+	err := formatted(source, nil, format, args...)
+	err.values = contextValues(ctx)
+	return codedError{
+		baseError: baseError{
+			wrapped: err,
+			source:  source,
+		},
+		code: code,
+	}
+}
+
+// RetryfCtx() is like Retryf() but also records values from ctx, like the request ID, see RegisterExtractor()
+func RetryfCtx(ctx context.Context, wait time.Duration, format string, args ...interface{}) RetryableError {
+	source := GetCaller(2)
+	err := formatted(source, nil, format, args...)
+	err.values = contextValues(ctx)
+	return retryableError{
+		baseError: baseError{
+			wrapped: err,
+			source:  source,
+		},
+		at: time.Now().Add(wait),
+	}
+}
+
+// PublicCtx() is like Public() but also records values from ctx, like the request ID, see RegisterExtractor()
+func PublicCtx(ctx context.Context, msg string) BaseError {
+	return &msgError{
+		baseError: baseError{
+			source: GetCaller(2),
+		},
+		msg:    msg,
+		public: true,
+		values: contextValues(ctx),
+	}
+}
+
+// PublicfCtx() is like Publicf() but also records values from ctx, like the request ID, see RegisterExtractor()
+func PublicfCtx(ctx context.Context, format string, args ...interface{}) BaseError {
+	err := formatted(GetCaller(2), nil, format, args...)
+	err.public = true
+	err.values = contextValues(ctx)
+	return err
+}
+
+// WrapPublicCtx() is like WrapPublic() but also records values from ctx, like the request ID, see RegisterExtractor()
+func WrapPublicCtx(ctx context.Context, err error, msg string) BaseError {
+	if err == nil {
+		return nil
+	}
+	return &msgError{
+		baseError: baseError{
+			wrapped: err,
+			source:  GetCaller(2),
+		},
+		msg:    msg,
+		public: true,
+		values: contextValues(ctx),
+	}
+}
+
+// WrapPublicfCtx() is like WrapPublicf() but also records values from ctx, like the request ID, see RegisterExtractor()
+func WrapPublicfCtx(ctx context.Context, err error, format string, args ...interface{}) BaseError {
+	if err == nil {
+		return nil
+	}
+	perr := formatted(GetCaller(2), err, format, args...)
+	perr.public = true
+	perr.values = contextValues(ctx)
+	return perr
+}
+
+// ContextValue is a value recorded from the context where an error was created, see WrapCtx()
+type ContextValue struct {
+	Name  string
+	Value string
+}
+
+// ContextValues() returns the values recorded from contexts in err and its wrapped errors,
+// with the outermost value when more than one error recorded the same name
+func ContextValues(err error) []ContextValue {
+	var values []ContextValue
+	for err != nil {
+		if ce, ok := err.(interface{ contextValues() []ContextValue }); ok {
+			for _, v := range ce.contextValues() {
+				if !slices.ContainsFunc(values, func(o ContextValue) bool { return o.Name == v.Name }) {
+					values = append(values, v)
+				}
+			}
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return values
+}
+
+// Extractor returns a value from ctx to record in errors, see RegisterExtractor()
+type Extractor func(ctx context.Context) (value string, ok bool)
+
+type namedExtractor struct {
+	name string
+	fn   Extractor
+}
+
+var (
+	extractorsMutex sync.Mutex //for changes, as extractors are read without locking
+	extractors      atomic.Pointer[[]namedExtractor]
+)
+
+func init() {
+	extractors.Store(&[]namedExtractor{
+		{name: "request_id", fn: contextString(requestIDKey{})},
+		{name: "tenant", fn: contextString(tenantKey{})},
+		{name: "traceparent", fn: contextString(traceparentKey{})},
+	})
+}
+
+// RegisterExtractor() adds a value that is recorded from the context by ErrorCtx(), WrapCtx() and their variants,
+// or replaces the extractor with the same name, or removes it when fn is nil.
+// The defaults are "request_id", "tenant" and "traceparent", set with WithRequestID(), WithTenant() and WithTraceparent().
+// Register your own extractors when starting, e.g. to read the trace from your tracing library.
+// It returns the extractor that was replaced or removed, or nil, e.g. to restore it after a test.
+func RegisterExtractor(name string, fn Extractor) (previous Extractor) {
+	extractorsMutex.Lock()
+	defer extractorsMutex.Unlock()
+	list := slices.DeleteFunc(slices.Clone(*extractors.Load()), func(e namedExtractor) bool {
+		if e.name == name {
+			previous = e.fn
+			return true
+		}
+		return false
+	})
+	if fn != nil {
+		list = append(list, namedExtractor{name: name, fn: fn})
+	}
+	extractors.Store(&list)
+	return previous
+}
+
+type requestIDKey struct{}
+type tenantKey struct{}
+type traceparentKey struct{}
+
+// WithRequestID() returns ctx with the request ID that is recorded in errors as "request_id"
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// WithTenant() returns ctx with the tenant that is recorded in errors as "tenant"
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// WithTraceparent() returns ctx with the W3C traceparent header of the request that is recorded in errors as "traceparent"
+func WithTraceparent(ctx context.Context, traceparent string) context.Context {
+	return context.WithValue(ctx, traceparentKey{}, traceparent)
+}
+
+func contextString(key any) Extractor {
+	return func(ctx context.Context) (string, bool) {
+		s, ok := ctx.Value(key).(string)
+		return s, ok
+	}
+}
+
+// contextValues runs the extractors on ctx
+func contextValues(ctx context.Context) []ContextValue {
+	if ctx == nil {
+		return nil
+	}
+	var values []ContextValue
+	for _, e := range *extractors.Load() {
+		if value, ok := e.fn(ctx); ok && value != "" {
+			values = append(values, ContextValue{Name: e.name, Value: value})
+		}
+	}
+	return values
+}
+
+// formatValues returns values like " [request_id=abc tenant=acme]" to write after the message, or ""
+func formatValues(values []ContextValue) string {
+	if len(values) == 0 {
+		return ""
+	}
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.Name + "=" + v.Value
+	}
+	return " [" + strings.Join(s, " ") + "]"
+}
@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	ctx := WithTenant(WithRequestID(context.Background(), "req-1"), "acme")
+	_, origErr := os.Open("/some/file/that/does/not/exist")
+	err := WrapCtx(ctx, origErr, "cannot load config")
+	err = WrapfCtx(WithRequestID(ctx, "req-2"), err, "cannot create user %s", "jan")
+
+	assert.Equal(t, "cannot create user jan because cannot load config because "+origErr.Error(), err.Error())
+	assert.Equal(t, "cannot create user jan because cannot load config because "+origErr.Error(), fmt.Sprintf("%+s", err))
+	assert.Equal(t, "context_test.go(17):cannot create user jan [request_id=req-2 tenant=acme] because "+
+		"context_test.go(16):cannot load config [request_id=req-1 tenant=acme] because "+origErr.Error(), fmt.Sprintf("%+v", err))
+	assert.Equal(t, []ContextValue{{Name: "request_id", Value: "req-2"}, {Name: "tenant", Value: "acme"}}, ContextValues(err))
+	assert.Equal(t, []ContextValue{{Name: "request_id", Value: "req-1"}, {Name: "tenant", Value: "acme"}}, ContextValues(Wrap(Unwrap(err), "outer")))
+
+	frames := Frames(err)
+	assert.Equal(t, map[string]string{"request_id": "req-2", "tenant": "acme"}, frames[0].Context)
+	assert.Equal(t, map[string]string{"request_id": "req-1", "tenant": "acme"}, frames[1].Context)
+	assert.Nil(t, frames[2].Context)
+
+	//without values
+	err = ErrorfCtx(context.Background(), "id %s not found", "x")
+	assert.Equal(t, "context_test.go(32):id x not found", fmt.Sprintf("%v", err))
+	assert.Nil(t, ContextValues(err))
+	assert.Nil(t, ContextValues(nil))
+	assert.Nil(t, WrapCtx(ctx, nil, "ignored"))
+	assert.Nil(t, WrapfCtx(ctx, nil, "ignored"))
+}
+
+func TestContextConstructors(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	values := []ContextValue{{Name: "request_id", Value: "req-1"}}
+	_, origErr := os.Open("/some/file/that/does/not/exist")
+
+	cerr := CodefCtx(ctx, 404, "user %s not found", "jan")
+	assert.Equal(t, 404, cerr.Code())
+	assert.Equal(t, "user jan not found", cerr.Error())
+	assert.Equal(t, values, ContextValues(cerr))
+
+	rerr := RetryfCtx(ctx, time.Second, "busy %d", 3)
+	assert.True(t, IsRetryable(rerr))
+	assert.Equal(t, "busy 3", rerr.Error())
+	assert.Equal(t, values, ContextValues(rerr))
+
+	for _, err := range []error{
+		PublicCtx(ctx, "user not found"),
+		PublicfCtx(ctx, "user %s not found", "jan"),
+		WrapPublicCtx(ctx, origErr, "user not found"),
+		WrapPublicfCtx(ctx, origErr, "user %s not found", "jan"),
+	} {
+		assert.Contains(t, UserMessage(err), "not found")
+		assert.Equal(t, values, ContextValues(err))
+		assert.Equal(t, "TestContextConstructors", Frames(err)[0].Function)
+	}
+	assert.Nil(t, WrapPublicCtx(ctx, nil, "ignored"))
+	assert.Nil(t, WrapPublicfCtx(ctx, nil, "ignored"))
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	type userKey struct{}
+	RegisterExtractor("user", func(ctx context.Context) (string, bool) {
+		user, ok := ctx.Value(userKey{}).(string)
+		return user, ok
+	})
+	tenant := RegisterExtractor("tenant", nil)
+	assert.NotNil(t, tenant)
+	defer func() {
+		assert.NotNil(t, RegisterExtractor("user", nil))
+		RegisterExtractor("tenant", tenant)
+	}()
+
+	ctx := WithTraceparent(WithTenant(context.WithValue(context.Background(), userKey{}, "jan"), "acme"),
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	err := ErrorCtx(ctx, "access denied")
+	assert.Equal(t, []ContextValue{
+		{Name: "traceparent", Value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{Name: "user", Value: "jan"},
+	}, ContextValues(err))
+}
@@ -1,9 +1,11 @@
 package errors
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -111,6 +113,109 @@ func TestWrappedAndFormatted(t *testing.T) {
 	assert.Equal(t, errWithSource+linkNl+errOrgWithSource, fmt.Sprintf("%-V", err)) //+ recurse on wrapped errors with newline
 }
 
+type countStringer struct {
+	count *int
+}
+
+func (s countStringer) String() string {
+	*s.count++
+	return "counted"
+}
+
+func TestTemplate(t *testing.T) {
+	count := 0
+	err := Wrapf(Errorf("id %s not found", "123"), "cannot get %s(%v)", "user", countStringer{&count})
+	assert.Equal(t, 0, count) //not yet formatted
+	assert.Equal(t, "cannot get %s(%v)", Template(err))
+	assert.Equal(t, []any{"user", countStringer{&count}}, Args(err))
+	assert.Equal(t, "id %s not found", Template(Unwrap(err)))
+	assert.Equal(t, []any{"123"}, Args(Unwrap(err)))
+
+	//formatted once when first used
+	assert.Equal(t, "cannot get user(counted) because id 123 not found", err.Error())
+	assert.Equal(t, "cannot get user(counted)", fmt.Sprintf("%s", err))
+	assert.Equal(t, 1, count)
+
+	//not formatted
+	assert.Equal(t, "some error", Template(Error("some error")))
+	assert.Nil(t, Args(Error("some error")))
+
+	//coded and retryable
+	assert.Equal(t, "id %s not found", Template(Codef(404, "id %s not found", "123")))
+	assert.Equal(t, []any{5}, Args(Code(Retryf(time.Second, "busy(%d)", 5), 503)))
+
+	//not from this package
+	assert.Equal(t, "", Template(os.ErrNotExist))
+	assert.Nil(t, Args(os.ErrNotExist))
+}
+
+func TestWrapVerb(t *testing.T) {
+	_, origErr := os.Open("/some/file/that/does/not/exist")
+	notFoundLine := GetCaller(1).Line() + 1
+	notFound := Error("not found")
+
+	//one %w
+	expErrLine := GetCaller(1).Line() + 1 //+1 because err is defined in the next line of this test
+	err := Errorf("loading %s: %w", "config", origErr)
+	assert.True(t, Is(err, os.ErrNotExist))
+	assert.Equal(t, origErr, Unwrap(err))
+	assert.Equal(t, "loading config: "+origErr.Error(), err.Error())
+	assert.Equal(t, "loading config: "+origErr.Error(), fmt.Sprintf("%+s", err)) //not repeated
+
+	//errors from other packages are not repeated with %+v either
+	assert.Equal(t, fmt.Sprintf("errors_test.go(%d):loading config: %s", expErrLine, origErr.Error()), fmt.Sprintf("%+v", err))
+	assert.Equal(t, fmt.Sprintf("errors_test.go(%d):loading: context canceled", GetCaller(1).Line()), fmt.Sprintf("%+v", Errorf("loading: %w", context.Canceled)))
+
+	//errors from this package are written again with their source reference
+	err = Errorf("loading: %w", notFound)
+	assert.Equal(t, fmt.Sprintf("errors_test.go(%d):loading: not found because errors_test.go(%d):not found", GetCaller(1).Line()-1, notFoundLine), fmt.Sprintf("%+v", err))
+
+	//many %w
+	err = Errorf("%w and %w", notFound, origErr)
+	assert.True(t, Is(err, os.ErrNotExist))
+	assert.True(t, Is(err, notFound))
+	assert.Equal(t, "not found and "+origErr.Error(), err.Error())
+	assert.Equal(t, fmt.Sprintf("errors_test.go(%d):not found and %s because errors_test.go(%d):not found", GetCaller(1).Line()-4, origErr.Error(), notFoundLine), fmt.Sprintf("%+v", err))
+
+	//Wrapf with %w writes the wrapped error after the message
+	err = Wrapf(origErr, "cannot load(%w)", notFound)
+	assert.True(t, Is(err, os.ErrNotExist))
+	assert.True(t, Is(err, notFound))
+	assert.Equal(t, "cannot load(not found) because "+origErr.Error(), err.Error())
+	assert.Equal(t, "cannot load(not found) because "+origErr.Error(), fmt.Sprintf("%+s", err))
+
+	//%w of a joined error is written once, not also after the message
+	joined := join(GetCaller(1), notFound, origErr)
+	err = Errorf("failed: %w", joined)
+	assert.True(t, Is(err, os.ErrNotExist))
+	assert.Equal(t, "failed: "+joined.Error(), err.Error())
+	assert.Equal(t, "failed: "+joined.Error(), fmt.Sprintf("%+s", err))
+
+	//coded and retryable
+	err = Codef(404, "user %s: %w", "jan", notFound)
+	assert.True(t, Is(err, notFound))
+	assert.Equal(t, "user jan: not found", fmt.Sprintf("%+s", err))
+	err = Retryf(time.Second, "busy: %w", origErr)
+	assert.True(t, Is(err, os.ErrNotExist))
+	assert.True(t, IsRetryable(err))
+
+	//public messages are not repeated
+	err = Publicf("cannot get user: %w", Public("not found"))
+	assert.Equal(t, "cannot get user: not found", UserMessage(err))
+
+	//explicit argument index and width from args
+	err = Errorf("%[2]s: %[1]w", notFound, "user")
+	assert.True(t, Is(err, notFound))
+	assert.Equal(t, "user: not found", err.Error())
+	err = Errorf("%*d %w", 3, 5, notFound)
+	assert.True(t, Is(err, notFound))
+	assert.Equal(t, "  5 not found", err.Error())
+
+	//not a wrap verb
+	assert.Equal(t, "100%w", Errorf("100%%w").Error())
+	assert.Nil(t, Unwrap(Errorf("100%%w")))
+}
+
 // func TestErrorIs(t *testing.T) {
 // 	//define two type of errors
 // 	e1 := Error("error1")
@@ -0,0 +1,184 @@
+// Package errorstyle defines an Analyzer that checks the messages passed to
+// github.com/go-msvc/errors follow the conventions in its README, so they read well
+// when wrapped errors are linked with " because ":
+//
+//   - start with a lowercase letter, unless the first word is an acronym like "JSON",
+//   - no trailing punctuation or white space,
+//   - no "because" or "failed to", as linking the wrapped error already says that,
+//   - no "invalid request" in Validate() methods, as the caller adds that context,
+//   - json names instead of Go field names of the receiver type.
+//
+// Fixes are suggested where they are mechanical.
+package errorstyle
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-msvc/errors/errorsvet"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorstyle",
+	Doc:      "check error messages passed to github.com/go-msvc/errors follow the README conventions",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+	}
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn := errorsvet.Func(pass.TypesInfo, call, errorsvet.PkgPath)
+		if fn == nil {
+			return true
+		}
+		i := errorsvet.FormatIndex(fn)
+		if i < 0 {
+			i = errorsvet.MessageIndex(fn)
+		}
+		if i < 0 || i >= len(call.Args) {
+			return true
+		}
+		lit, ok := ast.Unparen(call.Args[i]).(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		msg, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		var method *ast.FuncDecl
+		for i := len(stack) - 1; i >= 0; i-- {
+			if decl, ok := stack[i].(*ast.FuncDecl); ok {
+				if decl.Recv != nil {
+					method = decl
+				}
+				break
+			}
+		}
+		checkMessage(pass, lit, msg, method)
+		return true
+	})
+	return nil, nil
+} //run()
+
+func checkMessage(pass *analysis.Pass, lit *ast.BasicLit, msg string, method *ast.FuncDecl) {
+	//each problem is reported, and all mechanical fixes are suggested together
+	//with the first one, as they edit the same literal
+	problems := []string{}
+	fixed := msg
+
+	if first, size := utf8.DecodeRuneInString(fixed); unicode.IsUpper(first) {
+		if next, _ := utf8.DecodeRuneInString(fixed[size:]); !unicode.IsUpper(next) && !unicode.IsDigit(next) {
+			problems = append(problems, "error message should start with a lowercase letter")
+			fixed = string(unicode.ToLower(first)) + fixed[size:]
+		}
+	}
+	if trimmed := strings.TrimRight(fixed, ".!:;, \t\r\n"); trimmed != fixed && trimmed != "" {
+		problems = append(problems, "error message should not end with punctuation or white space")
+		fixed = trimmed
+	}
+	if strings.HasPrefix(fixed, "failed to ") {
+		problems = append(problems, "error message should say \"cannot\" rather than \"failed to\", which stutters when linked with \" because \"")
+		fixed = "cannot " + strings.TrimPrefix(fixed, "failed to ")
+	}
+	if method != nil {
+		names := jsonNames(pass, method)
+		fields := make([]string, 0, len(names))
+		for field := range names {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			re := regexp.MustCompile(`\b` + regexp.QuoteMeta(field) + `\b`)
+			if re.MatchString(fixed) {
+				problems = append(problems, "error message should refer to the json name "+strconv.Quote(names[field])+" rather than the field name "+field)
+				fixed = re.ReplaceAllLiteralString(fixed, names[field])
+			}
+		}
+	}
+	for i, problem := range problems {
+		if i == 0 {
+			report(pass, lit, problem, fixed)
+		} else {
+			report(pass, lit, problem, "")
+		}
+	}
+
+	//problems without a mechanical fix
+	if because.MatchString(msg) {
+		report(pass, lit, "error message should not say \"because\", the wrapped error is linked with \" because \"", "")
+	}
+	if method != nil && method.Name.Name == "Validate" && strings.HasPrefix(msg, "invalid request") {
+		report(pass, lit, "Validate() should only describe the field that failed, the caller adds \"invalid request\"", "")
+	}
+} //checkMessage()
+
+var because = regexp.MustCompile(`\bbecause\b`)
+
+// report the message on lit, with a fix that replaces the literal when fixed is not ""
+func report(pass *analysis.Pass, lit *ast.BasicLit, message, fixed string) {
+	diag := analysis.Diagnostic{
+		Pos:     lit.Pos(),
+		End:     lit.End(),
+		Message: message,
+	}
+	if fixed != "" {
+		value := strconv.Quote(fixed)
+		if strings.HasPrefix(lit.Value, "`") && !strings.Contains(fixed, "`") {
+			value = "`" + fixed + "`"
+		}
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Fix the error message style",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     lit.Pos(),
+				End:     lit.End(),
+				NewText: []byte(value),
+			}},
+		}}
+	}
+	pass.Report(diag)
+}
+
+// jsonNames returns the field names of the method's receiver struct
+// that have a different json name, with their json names
+func jsonNames(pass *analysis.Pass, method *ast.FuncDecl) map[string]string {
+	if len(method.Recv.List) == 0 {
+		return nil
+	}
+	t := pass.TypesInfo.TypeOf(method.Recv.List[0].Type)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	names := map[string]string{}
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		name, _, _ := strings.Cut(reflect.StructTag(st.Tag(i)).Get("json"), ",")
+		if name != "" && name != "-" && name != f.Name() {
+			names[f.Name()] = name
+		}
+	}
+	return names
+}
@@ -0,0 +1,12 @@
+package errorstyle_test
+
+import (
+	"testing"
+
+	"github.com/go-msvc/errors/errorstyle"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), errorstyle.Analyzer, "a")
+}
@@ -0,0 +1,13 @@
+module github.com/go-msvc/errors/errorstyle
+
+go 1.24.0
+
+require (
+	github.com/go-msvc/errors/errorsvet v0.1.0
+	golang.org/x/tools v0.42.0
+)
+
+require (
+	golang.org/x/mod v0.33.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+)
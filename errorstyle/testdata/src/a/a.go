@@ -0,0 +1,41 @@
+package a
+
+import (
+	"github.com/go-msvc/errors/v2"
+)
+
+var errTaken = errors.Error("Already taken") // want `error message should start with a lowercase letter`
+
+var errJSON = errors.Error("JSON is not valid") //ok, acronym
+
+func load(err error, name string) error {
+	if name == "" {
+		return errors.Error("missing name.") // want `error message should not end with punctuation or white space`
+	}
+	if name == "x" {
+		return errors.Wrapf(err, "Failed to load %s:", name) // want `error message should start with a lowercase letter` `error message should not end with punctuation or white space` `should say "cannot"`
+	}
+	if name == "y" {
+		return errors.Wrap(err, "failed to load") // want `error message should say "cannot" rather than "failed to"`
+	}
+	return errors.Errorf("cannot load %s because it is broken", name) // want `error message should not say "because"`
+}
+
+type Address struct {
+	Street string `json:"street"`
+	Town   string `json:"town,omitempty"`
+	Code   string
+}
+
+func (addr Address) Validate() error {
+	if addr.Street == "" {
+		return errors.Error("missing Street") // want `error message should refer to the json name "street" rather than the field name Street`
+	}
+	if addr.Code == "" {
+		return errors.Error("missing Code") //ok, no json name
+	}
+	if addr.Town == "" {
+		return errors.Error("invalid request because town is missing") // want `error message should not say "because"` `Validate\(\) should only describe the field that failed`
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package errors is a stub with the signatures of github.com/go-msvc/errors/v2 used in tests
+package errors
+
+import "time"
+
+type BaseError interface {
+	error
+	String() string
+}
+
+type CodedError interface {
+	BaseError
+	Code() int
+}
+
+type RetryableError interface {
+	BaseError
+	CanRetryAt() time.Time
+}
+
+func New(msg string) BaseError                                                     { return nil }
+func Error(msg string) BaseError                                                   { return nil }
+func Errorf(format string, args ...interface{}) BaseError                          { return nil }
+func Wrap(err error, msg string) BaseError                                         { return nil }
+func Wrapf(err error, format string, args ...interface{}) BaseError                { return nil }
+func Code(err error, code int) CodedError                                          { return nil }
+func Codef(code int, format string, args ...interface{}) CodedError                { return nil }
+func Retry(err error, wait time.Duration) RetryableError                           { return nil }
+func Retryf(wait time.Duration, format string, args ...interface{}) RetryableError { return nil }
+func Unwrap(err error) error                                                       { return nil }
+func HasCode(err error) bool                                                       { return false }
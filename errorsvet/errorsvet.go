@@ -0,0 +1,278 @@
+// Package errorsvet defines an Analyzer that reports misuse of github.com/go-msvc/errors:
+//
+//   - fmt.Errorf() where errors.Wrapf() or errors.Errorf() would record the source reference,
+//   - %w in functions that do not format the message, like Wrap(),
+//   - discarded results, like calling errors.Wrap(err, "...") without using the result,
+//   - wrapping the same error twice in one block, which writes the failure twice.
+//
+// Format strings that do not match the args of Errorf(), Wrapf(), Codef(), Retryf() etc.
+// are reported by the printf analyzer of go vet, which recognises them as wrappers of fmt.Errorf(),
+// and which the errorsvet command also runs.
+//
+// Use it as a library with other analyzers, or run it with go vet:
+//
+//	go install github.com/go-msvc/errors/cmd/errorsvet@latest
+//	go vet -vettool=$(which errorsvet) ./...
+package errorsvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// PkgPath is the import path of the package checked by the analyzers
+const PkgPath = "github.com/go-msvc/errors/v2"
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorsvet",
+	Doc:      "report misuse of github.com/go-msvc/errors that loses source references or garbles messages",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if !imports(pass.Pkg, PkgPath) {
+		return nil, nil
+	}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.ExprStmt)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.BlockStmt)(nil),
+		(*ast.CaseClause)(nil),
+		(*ast.CommClause)(nil),
+	}
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			checkCall(pass, n)
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok {
+				checkDiscarded(pass, call)
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) == 1 && len(n.Rhs) == 1 && isBlank(n.Lhs[0]) {
+				if call, ok := n.Rhs[0].(*ast.CallExpr); ok {
+					checkDiscarded(pass, call)
+				}
+			}
+		case *ast.BlockStmt:
+			checkWrappedTwice(pass, n.List)
+		case *ast.CaseClause:
+			checkWrappedTwice(pass, n.Body)
+		case *ast.CommClause:
+			checkWrappedTwice(pass, n.Body)
+		}
+	})
+	return nil, nil
+} //run()
+
+func imports(pkg *types.Package, path string) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Func returns the function called in call when it is a package-level function of pkgPath
+func Func(info *types.Info, call *ast.CallExpr, pkgPath string) *types.Func {
+	fn := callee(info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return nil
+	}
+	if fn.Type().(*types.Signature).Recv() != nil {
+		return nil
+	}
+	return fn
+}
+
+func callee(info *types.Info, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn
+}
+
+// FormatIndex returns the index of the format param of fn, when fn formats its message
+// like Errorf(format string, args ...any), else -1
+func FormatIndex(fn *types.Func) int {
+	sig := fn.Type().(*types.Signature)
+	params := sig.Params()
+	if !sig.Variadic() || params.Len() < 2 {
+		return -1
+	}
+	format := params.At(params.Len() - 2)
+	if format.Name() != "format" || !types.Identical(format.Type(), types.Typ[types.String]) {
+		return -1
+	}
+	return params.Len() - 2
+}
+
+// MessageIndex returns the index of the msg param of fn, when fn does not format its message
+// like Wrap(err error, msg string), else -1
+func MessageIndex(fn *types.Func) int {
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i).Name() == "msg" && types.Identical(params.At(i).Type(), types.Typ[types.String]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// wrappedIndex returns the index of the err param of fn when fn wraps it in an error type of this package,
+// like Wrap(err error, msg string) BaseError or WrapCtx(ctx context.Context, err error, msg string) BaseError,
+// else -1
+func wrappedIndex(fn *types.Func) int {
+	sig := fn.Type().(*types.Signature)
+	if sig.Results().Len() != 1 {
+		return -1
+	}
+	named, ok := sig.Results().At(0).Type().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != PkgPath || !isError(named) {
+		return -1
+	}
+	for i := 0; i < sig.Params().Len() && i < 2; i++ {
+		if p := sig.Params().At(i); p.Name() == "err" && isError(p.Type()) {
+			return i
+		}
+	}
+	return -1
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func isError(t types.Type) bool {
+	return types.Implements(t, errorType)
+}
+
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	if fn := Func(pass.TypesInfo, call, "fmt"); fn != nil && fn.Name() == "Errorf" {
+		if format, ok := constString(pass, call.Args[0]); ok && wrapVerbs(format) > 0 {
+			pass.ReportRangef(call, "fmt.Errorf does not record the source reference, use errors.Wrapf")
+		} else {
+			pass.ReportRangef(call, "fmt.Errorf does not record the source reference, use errors.Errorf")
+		}
+		return
+	}
+
+	fn := Func(pass.TypesInfo, call, PkgPath)
+	if fn == nil {
+		return
+	}
+	if i := MessageIndex(fn); i >= 0 && i < len(call.Args) {
+		if msg, ok := constString(pass, call.Args[i]); ok && wrapVerbs(msg) > 0 {
+			formatting := fn.Name() + "f"
+			if name, ok := strings.CutSuffix(fn.Name(), "Ctx"); ok {
+				formatting = name + "fCtx"
+			}
+			pass.ReportRangef(call.Args[i], "%s does not format its message, so %%w does not wrap, use %s", fn.Name(), formatting)
+		}
+	}
+} //checkCall()
+
+func constString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func checkDiscarded(pass *analysis.Pass, call *ast.CallExpr) {
+	fn := Func(pass.TypesInfo, call, PkgPath)
+	if fn == nil {
+		return
+	}
+	results := fn.Type().(*types.Signature).Results()
+	if results.Len() == 1 && isError(results.At(0).Type()) {
+		pass.ReportRangef(call, "result of errors.%s is not used", fn.Name())
+	}
+}
+
+func isBlank(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// checkWrappedTwice reports an error variable that is wrapped again in the same statement list
+// without being assigned in between, e.g. to log one wrapped error and return another
+func checkWrappedTwice(pass *analysis.Pass, list []ast.Stmt) {
+	wrapped := map[types.Object]bool{}
+	for _, stmt := range list {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt, *ast.FuncLit, *ast.CaseClause, *ast.CommClause:
+				return false //checked as their own statement lists
+			case *ast.CallExpr:
+				fn := Func(pass.TypesInfo, n, PkgPath)
+				if fn == nil {
+					return true
+				}
+				i := wrappedIndex(fn)
+				if i < 0 || i >= len(n.Args) {
+					return true
+				}
+				id, ok := ast.Unparen(n.Args[i]).(*ast.Ident)
+				if !ok {
+					return true
+				}
+				obj := pass.TypesInfo.ObjectOf(id)
+				if obj == nil {
+					return true
+				}
+				if wrapped[obj] {
+					pass.ReportRangef(n, "%s is already wrapped in this block, so the failure will be written twice", id.Name)
+				}
+				wrapped[obj] = true
+			}
+			return true
+		})
+		//assigning the variable after wrapping it, allows it to be wrapped again
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt, *ast.FuncLit, *ast.CaseClause, *ast.CommClause:
+				return false
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						delete(wrapped, pass.TypesInfo.ObjectOf(id))
+					}
+				}
+			}
+			return true
+		})
+	}
+} //checkWrappedTwice()
+
+// wrapVerbs returns the number of %w verbs in format
+func wrapVerbs(format string) int {
+	n := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0; i++ {
+		}
+		if i < len(format) && format[i] == 'w' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,19 @@
+package errorsvet_test
+
+import (
+	"testing"
+
+	"github.com/go-msvc/errors/errorsvet"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/passes/printf"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), errorsvet.Analyzer, "a")
+}
+
+// TestPrintf checks that the printf analyzer run by the errorsvet command
+// reports format strings that do not match the args
+func TestPrintf(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), printf.Analyzer, "p")
+}
@@ -0,0 +1,65 @@
+package a
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+func open(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %w", name, err) // want `fmt.Errorf does not record the source reference, use errors.Wrapf`
+	}
+	f.Close()
+	return fmt.Errorf("not implemented") // want `fmt.Errorf does not record the source reference, use errors.Errorf`
+}
+
+func formats(id string, err error) {
+	_ = errors.Errorf("id %s not found", id) // want `result of errors.Errorf is not used`
+	errors.Errorf("id %s not found", id)     // want `result of errors.Errorf is not used`
+	errors.Wrap(err, "failed")               // want `result of errors.Wrap is not used`
+	errors.HasCode(err)
+
+	var e error
+	e = errors.Errorf("wrap %w", err)               //ok
+	e = errors.Wrap(os.ErrNotExist, "cannot do %w") // want `Wrap does not format its message, so %w does not wrap, use Wrapf`
+	_ = e
+}
+
+func wrapTwice(err error) error {
+	if err != nil {
+		wrapped := errors.Wrap(err, "failed")
+		fmt.Println(wrapped)
+		return errors.Wrap(err, "failed again") // want `err is already wrapped in this block, so the failure will be written twice`
+	}
+	switch {
+	case err == nil:
+		return nil
+	default:
+		err = errors.Wrap(err, "one")
+		err = errors.Code(err, 500) //ok, err was assigned
+		return errors.Retry(errors.Wrap(err, "three"), time.Second)
+	}
+}
+
+func branches(err error, x bool) error {
+	if x {
+		return errors.Wrap(err, "x")
+	}
+	return errors.Wrap(err, "not x") //ok, different block
+}
+
+func withContext(ctx context.Context, id string, err error) error {
+	e := errors.ErrorfCtx(ctx, "id %s not found", id)
+	e = errors.WrapCtx(ctx, e, "cannot do %w") // want `WrapCtx does not format its message, so %w does not wrap, use WrapfCtx`
+	_ = e
+	if err != nil {
+		fmt.Println(errors.WrapCtx(ctx, err, "failed"))
+		return errors.WrapfCtx(ctx, err, "failed %s", id) // want `err is already wrapped in this block, so the failure will be written twice`
+	}
+	return nil
+}
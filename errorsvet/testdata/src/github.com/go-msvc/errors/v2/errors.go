@@ -0,0 +1,54 @@
+// Package errors is a stub with the signatures of github.com/go-msvc/errors/v2 used in tests,
+// where the formatting functions call fmt.Errorf() to be printf wrappers like the real ones
+package errors
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type BaseError interface {
+	error
+	String() string
+}
+
+type CodedError interface {
+	BaseError
+	Code() int
+}
+
+type RetryableError interface {
+	BaseError
+	CanRetryAt() time.Time
+}
+
+func New(msg string) BaseError                            { return nil }
+func Error(msg string) BaseError                          { return nil }
+func Errorf(format string, args ...interface{}) BaseError { return msg(fmt.Errorf(format, args...)) }
+func Wrap(err error, msg string) BaseError                { return nil }
+func Wrapf(err error, format string, args ...interface{}) BaseError {
+	return msg(fmt.Errorf(format, args...))
+}
+func Code(err error, code int) CodedError { return nil }
+func Codef(code int, format string, args ...interface{}) CodedError {
+	return coded(fmt.Errorf(format, args...))
+}
+func Retry(err error, wait time.Duration) RetryableError { return nil }
+func Retryf(wait time.Duration, format string, args ...interface{}) RetryableError {
+	return retryable(fmt.Errorf(format, args...))
+}
+func Unwrap(err error) error                             { return nil }
+func HasCode(err error) bool                             { return false }
+func ErrorCtx(ctx context.Context, msg string) BaseError { return nil }
+func ErrorfCtx(ctx context.Context, format string, args ...interface{}) BaseError {
+	return msg(fmt.Errorf(format, args...))
+}
+func WrapCtx(ctx context.Context, err error, msg string) BaseError { return nil }
+func WrapfCtx(ctx context.Context, err error, format string, args ...interface{}) BaseError {
+	return msg(fmt.Errorf(format, args...))
+}
+
+func msg(error) BaseError            { return nil }
+func coded(error) CodedError         { return nil }
+func retryable(error) RetryableError { return nil }
@@ -0,0 +1,25 @@
+package p
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+func formats(ctx context.Context, id string, err error) []error {
+	args := []any{id}
+	return []error{
+		errors.Errorf("id %s not found", id, 5),                   // want `Errorf call needs 1 arg but has 2 args`
+		errors.Errorf("id %d not found", id),                      // want `Errorf format %d has arg id of wrong type string`
+		errors.Wrapf(os.ErrNotExist, "id %s not found in %s", id), // want `Wrapf format %s reads arg #2, but call has 1 arg`
+		errors.Codef(404, "id %[2]s of %[1]s", "x", id),           //ok
+		errors.Retryf(time.Second, "%*d%%", 5, 3),                 //ok
+		errors.Retryf(time.Second, "%d%%", 5, 3),                  // want `Retryf call needs 1 arg but has 2 args`
+		errors.Errorf("wrap %w", err),                             //ok
+		errors.Errorf("id %s %s", args...),                        //not checked
+		errors.ErrorfCtx(ctx, "id %d not found", id),              // want `ErrorfCtx format %d has arg id of wrong type string`
+		errors.WrapfCtx(ctx, err, "id %s", 5),                     // want `WrapfCtx format %s has arg 5 of wrong type int`
+	}
+}
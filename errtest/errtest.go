@@ -0,0 +1,117 @@
+// Package errtest has assertions for tests of code using github.com/go-msvc/errors,
+// which write the whole error chain when they fail.
+package errtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+// AssertChain() asserts the messages of err and its wrapped errors, outermost first:
+//
+//	errtest.AssertChain(t, err, "cannot create user", "invalid request", "missing name")
+//
+// An error from another package is the last message, with its Error() text.
+func AssertChain(t testing.TB, err error, msgs ...string) bool {
+	t.Helper()
+	actual := []string{}
+	for _, frame := range errors.Frames(err) {
+		actual = append(actual, frame.Message)
+	}
+	if slices.Equal(actual, msgs) {
+		return true
+	}
+	t.Errorf("error chain does not match (- expected, + actual):\n%s", diff(msgs, actual))
+	return false
+}
+
+// AssertCode() asserts that err has the code, see errors.GetCode()
+func AssertCode(t testing.TB, err error, code int) bool {
+	t.Helper()
+	actual, ok := errors.GetCode(err)
+	switch {
+	case !ok:
+		t.Errorf("error has no code, expected %d:\n%s", code, chain(err))
+	case actual != code:
+		t.Errorf("error has code %d, expected %d:\n%s", actual, code, chain(err))
+	default:
+		return true
+	}
+	return false
+}
+
+// AssertRetryableWithin() asserts that err can be retried, at the latest after d from now
+func AssertRetryableWithin(t testing.TB, err error, d time.Duration) bool {
+	t.Helper()
+	at, ok := errors.RetryableAt(err)
+	if !ok {
+		t.Errorf("error is not retryable:\n%s", chain(err))
+		return false
+	}
+	if wait := time.Until(at); wait > d {
+		t.Errorf("error can be retried after %v, expected within %v:\n%s", wait.Round(time.Millisecond), d, chain(err))
+		return false
+	}
+	return true
+}
+
+// AssertSourceHere() asserts that err was created or wrapped in the function calling AssertSourceHere(),
+// without depending on line numbers that change when the test is edited
+func AssertSourceHere(t testing.TB, err error) bool {
+	t.Helper()
+	here := errors.GetCaller(2)
+	be, ok := err.(errors.BaseError)
+	if !ok {
+		t.Errorf("error %T has no source reference, expected %s in %s():\n%s", err, filepath.Base(here.File()), here.Function(), chain(err))
+		return false
+	}
+	source := be.Source()
+	if source.File() != here.File() || source.Function() != here.Function() {
+		t.Errorf("error source is %s in %s(), expected %s in %s():\n%s",
+			source, source.Function(), filepath.Base(here.File()), here.Function(), chain(err))
+		return false
+	}
+	return true
+}
+
+// AssertIs() asserts that target is err or one of its wrapped errors, see errors.Is()
+func AssertIs(t testing.TB, err, target error) bool {
+	t.Helper()
+	if errors.Is(err, target) {
+		return true
+	}
+	t.Errorf("error is not %q:\n%s", target, chain(err))
+	return false
+}
+
+// chain returns the error chain as lines of messages with source references
+func chain(err error) string {
+	if err == nil {
+		return "  <nil>"
+	}
+	return "  " + strings.ReplaceAll(fmt.Sprintf("%-v", err), "\n", "\n  ")
+}
+
+// diff returns the expected and actual lines, marking the ones that differ
+func diff(expected, actual []string) string {
+	lines := []string{}
+	for i := 0; i < max(len(expected), len(actual)); i++ {
+		switch {
+		case i >= len(actual):
+			lines = append(lines, "- "+expected[i])
+		case i >= len(expected):
+			lines = append(lines, "+ "+actual[i])
+		case expected[i] == actual[i]:
+			lines = append(lines, "  "+expected[i])
+		default:
+			lines = append(lines, "- "+expected[i], "+ "+actual[i])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+package errtest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT records failures instead of failing the test
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func createUser() error {
+	return errors.Wrap(errors.Codef(400, "missing name"), "invalid request")
+}
+
+func TestAssertChain(t *testing.T) {
+	err := errors.Wrap(createUser(), "cannot create user")
+	assert.True(t, AssertChain(t, err, "cannot create user", "invalid request", "missing name"))
+
+	ft := &fakeT{}
+	assert.False(t, AssertChain(ft, err, "cannot create user", "invalid reqest", "missing name", "more"))
+	assert.Equal(t, []string{"error chain does not match (- expected, + actual):\n" +
+		"  cannot create user\n" +
+		"- invalid reqest\n" +
+		"+ invalid request\n" +
+		"  missing name\n" +
+		"- more"}, ft.errors)
+
+	_, openErr := os.Open("/some/file/that/does/not/exist")
+	assert.True(t, AssertChain(t, errors.Wrap(openErr, "cannot load"), "cannot load", openErr.Error()))
+}
+
+func TestAssertCode(t *testing.T) {
+	assert.True(t, AssertCode(t, createUser(), 400))
+
+	ft := &fakeT{}
+	assert.False(t, AssertCode(ft, createUser(), 404))
+	assert.False(t, AssertCode(ft, errors.Error("no code"), 404))
+	assert.Len(t, ft.errors, 2)
+	assert.Contains(t, ft.errors[0], "error has code 400, expected 404:\n  errtest_test.go(")
+	assert.Contains(t, ft.errors[1], "error has no code, expected 404")
+}
+
+func TestAssertRetryableWithin(t *testing.T) {
+	assert.True(t, AssertRetryableWithin(t, errors.Retryf(time.Second, "busy"), 5*time.Second))
+
+	ft := &fakeT{}
+	assert.False(t, AssertRetryableWithin(ft, errors.Retryf(time.Minute, "busy"), 5*time.Second))
+	assert.False(t, AssertRetryableWithin(ft, errors.Error("broken"), 5*time.Second))
+	assert.Len(t, ft.errors, 2)
+	assert.Contains(t, ft.errors[0], "expected within 5s")
+	assert.Contains(t, ft.errors[1], "error is not retryable")
+}
+
+func TestAssertSourceHere(t *testing.T) {
+	err := errors.Error("created here")
+	assert.True(t, AssertSourceHere(t, err))
+
+	ft := &fakeT{}
+	assert.False(t, AssertSourceHere(ft, createUser()))
+	assert.False(t, AssertSourceHere(ft, os.ErrNotExist))
+	assert.Len(t, ft.errors, 2)
+	assert.Contains(t, ft.errors[0], "in createUser(), expected errtest_test.go in TestAssertSourceHere()")
+	assert.Contains(t, ft.errors[1], "error *errors.errorString has no source reference")
+}
+
+func TestAssertIs(t *testing.T) {
+	_, openErr := os.Open("/some/file/that/does/not/exist")
+	assert.True(t, AssertIs(t, errors.Wrap(openErr, "cannot load"), os.ErrNotExist))
+
+	ft := &fakeT{}
+	assert.False(t, AssertIs(ft, createUser(), os.ErrNotExist))
+	assert.Contains(t, ft.errors[0], "error is not \"file does not exist\":\n  errtest_test.go(")
+}
@@ -0,0 +1,102 @@
+package errtest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+// the flag is prefixed with the package name, so it does not collide with an -update flag of the tests using errtest
+var update = flag.Bool("errtest.update", false, "update the golden files of errtest.Golden() in testdata")
+
+// Update() returns true when the test runs with -errtest.update, e.g. to also update your own golden files
+func Update() bool {
+	return *update
+}
+
+// verbs written to golden files
+var goldenVerbs = []string{"%s", "%+s", "%-s", "%u", "%v", "%+v", "%-V"}
+
+// Golden() compares all renderings of err with testdata/<test name>.golden,
+// or writes the file when the test runs with -errtest.update:
+//
+//	go test -run TestMyError -errtest.update
+//
+// Line numbers, the working directory and retry times are replaced with placeholders,
+// so the file does not change when the code is edited or the test runs at another time or place.
+// Use it once per test or sub-test.
+func Golden(t testing.TB, err error) bool {
+	t.Helper()
+	actual := golden(err)
+	file := filepath.Join("testdata", strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())+".golden")
+	if Update() {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("%+v", errors.Wrap(err, "cannot create testdata"))
+		}
+		if err := os.WriteFile(file, []byte(actual), 0644); err != nil {
+			t.Fatalf("%+v", errors.Wrapf(err, "cannot write %s", file))
+		}
+		return true
+	}
+	data, readErr := os.ReadFile(file)
+	if readErr != nil {
+		t.Errorf("%+v", errors.Wrapf(readErr, "cannot read golden file, run with -errtest.update to create it"))
+		return false
+	}
+	if expected := string(data); expected != actual {
+		t.Errorf("error does not match %s (- expected, + actual), run with -errtest.update if it should:\n%s",
+			file, diff(strings.Split(expected, "\n"), strings.Split(actual, "\n")))
+		return false
+	}
+	return true
+} //Golden()
+
+// golden returns the normalized renderings of err
+func golden(err error) string {
+	sb := strings.Builder{}
+	for _, verb := range goldenVerbs {
+		fmt.Fprintf(&sb, "-- %s --\n"+verb+"\n", verb, err)
+	}
+	data, _ := json.MarshalIndent(errors.Frames(err), "", "  ")
+	fmt.Fprintf(&sb, "-- json --\n%s\n", data)
+	return normalize(sb.String(), err)
+}
+
+var linePattern = regexp.MustCompile(`\.go\(\d+\)`)
+
+func normalize(s string, err error) string {
+	s = linePattern.ReplaceAllString(s, ".go(LINE)")
+	for _, at := range retryTimes(errors.Frames(err)) {
+		data, _ := json.Marshal(at)
+		s = strings.ReplaceAll(s, strings.Trim(string(data), `"`), "RETRY_AT")
+		s = strings.ReplaceAll(s, at.String(), "RETRY_AT")
+	}
+	if wd, err := os.Getwd(); err == nil {
+		s = strings.ReplaceAll(s, wd, "$PWD")
+	}
+	if tmp := strings.TrimSuffix(os.TempDir(), string(filepath.Separator)); tmp != "" {
+		s = strings.ReplaceAll(s, tmp, "$TMPDIR")
+	}
+	return s
+}
+
+func retryTimes(frames []errors.Frame) []time.Time {
+	times := []time.Time{}
+	for _, frame := range frames {
+		if frame.RetryAt != nil {
+			times = append(times, *frame.RetryAt)
+		}
+		for _, joined := range frame.Joined {
+			times = append(times, retryTimes(joined)...)
+		}
+	}
+	return times
+}
@@ -0,0 +1,44 @@
+package errtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// tests using errtest can define their own -update flag
+var _ = flag.Bool("update", false, "update other golden files")
+
+func loadConfig() error {
+	_, err := os.Open(filepath.Join(os.TempDir(), "errtest-missing", "config.json"))
+	return errors.Wrapf(errors.Retry(err, time.Minute), "cannot load %s", "config")
+}
+
+func TestGolden(t *testing.T) {
+	err := errors.Wrap(errors.Code(loadConfig(), 503), "cannot start")
+	assert.True(t, Golden(t, err))
+	if Update() {
+		return
+	}
+
+	//same test name, different error
+	ft := &fakeT{TB: t}
+	assert.False(t, Golden(ft, errors.Wrap(loadConfig(), "cannot start")))
+	if assert.Len(t, ft.errors, 1) {
+		assert.True(t, strings.HasPrefix(ft.errors[0], "error does not match testdata/TestGolden.golden (- expected, + actual), run with -errtest.update if it should:\n"), ft.errors[0])
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	wd, _ := os.Getwd()
+	err := errors.Retry(errors.Error("busy"), time.Hour)
+	at, _ := errors.RetryableAt(err)
+	s := normalize("golden_test.go(12) "+wd+"/x.go "+at.Format(time.RFC3339Nano)+" "+at.String(), err)
+	assert.Equal(t, "golden_test.go(LINE) $PWD/x.go RETRY_AT RETRY_AT", s)
+}
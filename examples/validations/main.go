@@ -1,14 +1,13 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
 
-	"github.com/go-msvc/errors/v2"
 	"github.com/go-msvc/errors/v2/examples/validations/users"
+	"github.com/go-msvc/errors/v2/httperr"
 )
 
 func main() {
@@ -22,53 +21,33 @@ func main() {
 }
 
 func addUser(httpRes http.ResponseWriter, httpReq *http.Request) {
-	if httpReq.Method != http.MethodPost {
-		http.Error(httpRes, "this is not a post", http.StatusMethodNotAllowed)
-		return
-	}
-	if httpReq.Body == nil {
-		http.Error(httpRes, "missing body", http.StatusBadRequest)
+	if err := httperr.Method(httpReq, http.MethodPost); err != nil {
+		httperr.Error(httpRes, err)
 		return
 	}
 	var req users.AddUserRequest
-	if err := json.NewDecoder(httpReq.Body).Decode(&req); err != nil {
-		http.Error(httpRes, fmt.Sprintf("cannot parse JSON body into %T: %+s", req, err), http.StatusBadRequest)
-		return
-	}
-
-	if err := req.Validate(); err != nil {
-		err = errors.Wrap(err, "invalid request")
+	if err := httperr.Decode(httpReq, &req); err != nil {
 		fmt.Fprintf(os.Stderr, "HTTP %s %s: %+v\n",
 			httpReq.Method,
 			httpReq.URL.Path,
-			err) //note use of %+v in log, but %+s below in user message
-		http.Error(httpRes, fmt.Sprintf("%+s", err), http.StatusBadRequest)
+			err) //note use of %+v in log, but only public messages in user response
+		httperr.Error(httpRes, err)
 		return
 	}
 }
 
 func updUser(httpRes http.ResponseWriter, httpReq *http.Request) {
-	if httpReq.Method != http.MethodPut {
-		http.Error(httpRes, "this is not a put", http.StatusMethodNotAllowed)
-		return
-	}
-	if httpReq.Body == nil {
-		http.Error(httpRes, "missing body", http.StatusBadRequest)
+	if err := httperr.Method(httpReq, http.MethodPut); err != nil {
+		httperr.Error(httpRes, err)
 		return
 	}
 	var req users.UpdateUserRequest
-	if err := json.NewDecoder(httpReq.Body).Decode(&req); err != nil {
-		http.Error(httpRes, fmt.Sprintf("cannot parse JSON body into %T: %+s", req, err), http.StatusBadRequest)
-		return
-	}
-
-	if err := req.Validate(); err != nil {
-		err = errors.Wrap(err, "invalid request")
+	if err := httperr.DecodeStrict(httpReq, &req); err != nil {
 		fmt.Fprintf(os.Stderr, "HTTP %s %s: %+v\n",
 			httpReq.Method,
 			httpReq.URL.Path,
-			err) //note use of %+v in log, but %+s below in user message
-		http.Error(httpRes, fmt.Sprintf("%+s", err), http.StatusBadRequest)
+			err) //note use of %+v in log, but only public messages in user response
+		httperr.Error(httpRes, err)
 		return
 	}
 }
@@ -13,13 +13,13 @@ type AddUserRequest struct {
 
 func (req AddUserRequest) Validate() error {
 	if req.Name == "" {
-		return errors.Error("missing name")
+		return errors.Public("missing name")
 	}
 	if req.DateOfBirth == "" {
-		return errors.Error("missing date-of-birth")
+		return errors.Public("missing date-of-birth")
 	}
 	if _, err := time.Parse("2006-01-02", req.DateOfBirth); err != nil {
-		return errors.Errorf("date-of-birth:\"%s\" not formatted as CCYY-MM-DD", req.DateOfBirth)
+		return errors.Publicf("date-of-birth:\"%s\" not formatted as CCYY-MM-DD", req.DateOfBirth)
 	}
 	return nil
 }
@@ -33,18 +33,18 @@ func (req UpdateUserRequest) Validate() error {
 	count := 0
 	if req.DateOfBirth != nil && *req.DateOfBirth != "" {
 		if _, err := time.Parse("2006-01-02", *req.DateOfBirth); err != nil {
-			return errors.Errorf("date-of-birth:\"%s\" not formatted as CCYY-MM-DD", *req.DateOfBirth)
+			return errors.Publicf("date-of-birth:\"%s\" not formatted as CCYY-MM-DD", *req.DateOfBirth)
 		}
 		count++
 	}
 	if req.Address != nil {
 		if err := req.Address.Validate(); err != nil {
-			return errors.Wrap(err, "invalid address")
+			return errors.WrapField(err, "address")
 		}
 		count++
 	}
 	if count == 0 {
-		return errors.Error("missing both date-of-birth and address")
+		return errors.Public("missing both date-of-birth and address")
 	}
 	return nil
 }
@@ -56,10 +56,10 @@ type Address struct {
 
 func (addr Address) Validate() error {
 	if addr.Street == "" {
-		return errors.Error("missing street")
+		return errors.Public("missing street")
 	}
 	if addr.Country == "" {
-		return errors.Error("missing country")
+		return errors.Public("missing country")
 	}
 	return nil
 }
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"strings"
+)
+
+// WrapField() wraps an error with the json path of the field that failed,
+// which reads like "invalid address because missing street".
+// Use the json tag names so the user knows what to fix in the document.
+// The message is public, see UserMessage().
+func WrapField(err error, field string) BaseError {
+	if err == nil {
+		return nil
+	}
+	return fieldError{
+		msgError: msgError{
+			baseError: baseError{
+				wrapped: err,
+				source:  GetCaller(2),
+			},
+			msg:    "invalid " + field,
+			public: true, //built from the json path, so safe to show to the user
+		},
+		field: field,
+	}
+}
+
+// FieldPath() returns the json path of the fields wrapped in err, e.g. "address.street",
+// or "" when no field is wrapped.
+// For joined errors, only the first path is returned.
+func FieldPath(err error) string {
+	path := []string{}
+	for err != nil {
+		if fe, ok := err.(fieldError); ok {
+			path = append(path, fe.field)
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			err = nil
+			for _, item := range e.Unwrap() {
+				if FieldPath(item) != "" {
+					err = item
+					break
+				}
+			}
+		default:
+			err = nil
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+type fieldError struct {
+	msgError
+	field string
+}
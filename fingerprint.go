@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+)
+
+// FingerprintOption changes what is included in Fingerprint()
+type FingerprintOption func(*fingerprintOptions)
+
+type fingerprintOptions struct {
+	lines bool
+}
+
+// WithLines() includes line numbers of source references in the fingerprint,
+// which is more specific, but changes when code is edited above the line
+func WithLines() FingerprintOption {
+	return func(o *fingerprintOptions) {
+		o.lines = true
+	}
+}
+
+// Fingerprint() returns a hash of err and all wrapped errors, to group the same failure
+// across deploys and hosts, e.g. for alerting.
+// It includes the package, function and file of each source reference, message templates (not the args),
+// codes, kinds and which errors are retryable, but not times or messages with variable values.
+// Errors from other packages are only identified by their type, as their messages may have variable values,
+// so wrap them with a message of this package to tell them apart.
+func Fingerprint(err error, opts ...FingerprintOption) string {
+	o := fingerprintOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	h := sha256.New()
+	fingerprint(h, o, err)
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+func fingerprint(h hash.Hash, o fingerprintOptions, err error) {
+	for err != nil {
+		if be, ok := err.(BaseError); ok {
+			source := be.Source()
+			io.WriteString(h, "at:"+source.Package()+"."+source.Function()+":"+source.PackageFile())
+			if o.lines {
+				io.WriteString(h, ":"+strconv.Itoa(source.Line()))
+			}
+		} else {
+			io.WriteString(h, "type:"+fmt.Sprintf("%T", err)) //not the message, which may have variable values
+		}
+		if te, ok := err.(templatedError); ok {
+			format, _ := te.template()
+			io.WriteString(h, "|msg:"+format)
+		}
+		if c, ok := err.(Coded); ok {
+			io.WriteString(h, "|code:"+strconv.Itoa(c.Code()))
+		}
+		if k, ok := err.(kindError); ok {
+			io.WriteString(h, "|kind:"+k.kind.String())
+		}
+		if _, ok := err.(Retryable); ok {
+			io.WriteString(h, "|retryable")
+		}
+		switch err.(type) {
+		case permanentError:
+			io.WriteString(h, "|permanent")
+		case ambiguousError:
+			io.WriteString(h, "|ambiguous")
+		case idempotentError:
+			io.WriteString(h, "|idempotent")
+		}
+
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			io.WriteString(h, "|join(")
+			for _, item := range e.Unwrap() {
+				fingerprint(h, o, item)
+				io.WriteString(h, ";")
+			}
+			io.WriteString(h, ")")
+			err = nil
+		default:
+			err = nil
+		}
+		io.WriteString(h, "\n")
+	}
+} //fingerprint()
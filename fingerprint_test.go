@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func notFound(id string) error {
+	return Codef(404, "user(%s) not found", id)
+}
+
+func TestFingerprint(t *testing.T) {
+	//same failure with different args
+	fp := Fingerprint(Wrap(notFound("1"), "cannot get user"))
+	assert.Len(t, fp, 16)
+	assert.Equal(t, fp, Fingerprint(Wrap(notFound("2"), "cannot get user")))
+
+	//same failure wrapped on different lines in the same function
+	err1 := Wrap(notFound("1"), "cannot get user")
+	err2 := Wrap(notFound("1"), "cannot get user")
+	assert.Equal(t, Fingerprint(err1), Fingerprint(err2))
+	assert.NotEqual(t, Fingerprint(err1, WithLines()), Fingerprint(err2, WithLines()))
+
+	//different template, code or retry
+	assert.NotEqual(t, fp, Fingerprint(Wrap(notFound("1"), "cannot update user")))
+	assert.NotEqual(t, fp, Fingerprint(Wrap(Code(notFound("1"), 500), "cannot get user")))
+	assert.Equal(t,
+		Fingerprint(Retry(Error("busy"), time.Second)),
+		Fingerprint(Retry(Error("busy"), time.Hour)))
+
+	//errors from other packages
+	_, openErr1 := os.Open("/some/file/that/does/not/exist")
+	_, openErr2 := os.Open("/other/file/that/does/not/exist")
+	assert.Equal(t, Fingerprint(openErr1), Fingerprint(openErr2))
+	assert.Equal(t, Fingerprint(errors.New("user 1 not found")), Fingerprint(errors.New("user 2 not found")), "only the type")
+	assert.NotEqual(t, Fingerprint(Wrap(errors.New("broken"), "cannot read")), Fingerprint(Wrap(errors.New("timeout"), "cannot write")))
+	assert.Equal(t, Fingerprint(fmt.Errorf("id %d: %w", 1, os.ErrNotExist)), Fingerprint(fmt.Errorf("id %d: %w", 2, os.ErrNotExist)))
+
+	//joined errors
+	j1 := join(GetCaller(1), notFound("1"), Error("other"))
+	j2 := join(GetCaller(1), notFound("2"), Error("other"))
+	j3 := join(GetCaller(1), Error("other"), notFound("1"))
+	assert.Equal(t, Fingerprint(j1), Fingerprint(j2))
+	assert.NotEqual(t, Fingerprint(j1), Fingerprint(j3))
+}
@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// Frame describes one error in a chain of wrapped errors, see Frames()
+type Frame struct {
+	Message    string            `json:"msg,omitempty"`
+	Source     string            `json:"source,omitempty"` //full path like "github.com/my/pkg/file.go(12)"
+	Function   string            `json:"func,omitempty"`
+	Code       int               `json:"code,omitempty"`
+	Kind       string            `json:"kind,omitempty"` //see WithKind()
+	RetryAt    *time.Time        `json:"retry_at,omitempty"`
+	Ambiguous  bool              `json:"ambiguous,omitempty"`  //see Ambiguous()
+	Idempotent bool              `json:"idempotent,omitempty"` //see Idempotent()
+	Permanent  bool              `json:"permanent,omitempty"`  //see Permanent()
+	Public     bool              `json:"public,omitempty"`
+	Field      string            `json:"field,omitempty"`
+	Context    map[string]string `json:"context,omitempty"` //see WrapCtx()
+	Joined     [][]Frame         `json:"joined,omitempty"`  //frames of each joined error
+}
+
+// Frames() returns err and its wrapped errors, outermost first, for structured output like JSON:
+//
+//	json.Marshal(errors.Frames(err))
+//
+// Codes, kinds, retry times and the permanent, ambiguous and idempotent markers are added to the frame of the error they wrap,
+// which keeps its own source.
+// Secret values are redacted in messages.
+// An error from another package is the last frame, with its Error() message.
+// Joined errors are the last frame, with the frames of each joined error.
+func Frames(err error) []Frame {
+	frames := []Frame{}
+	next := Frame{} //code, kind and retry time are added to the next frame
+	for err != nil {
+		if be, ok := err.(BaseError); ok && next.Source == "" {
+			//source of the outermost wrapper, only kept when the frame is an error from another package
+			next.Source = fmt.Sprintf("%v", be.Source())
+			next.Function = be.Source().Function()
+		}
+		switch e := err.(type) {
+		case codedError:
+			if next.Code == 0 {
+				next.Code = e.code
+			}
+			err = e.wrapped
+			continue
+		case ambiguousError:
+			next.Ambiguous = true
+			err = e.wrapped
+			continue
+		case idempotentError:
+			next.Idempotent = true
+			err = e.wrapped
+			continue
+		case permanentError:
+			next.Permanent = true
+			err = e.wrapped
+			continue
+		case kindError:
+			if next.Kind == "" {
+				next.Kind = e.kind.String()
+			}
+			err = e.wrapped
+			continue
+		case retryableError:
+			if next.RetryAt == nil {
+				at := e.at
+				next.RetryAt = &at
+			}
+			err = e.wrapped
+			continue
+		case joinedError:
+			next.Source = fmt.Sprintf("%v", e.source)
+			next.Function = e.source.Function()
+			for _, item := range e.errs {
+				next.Joined = append(next.Joined, Frames(item))
+			}
+			return append(frames, next)
+		case fieldError:
+			next.Field = e.field
+		}
+
+		if be, ok := err.(BaseError); ok {
+			next.Source = fmt.Sprintf("%v", be.Source())
+			next.Function = be.Source().Function()
+		}
+		switch e := err.(type) {
+		case interface{ publicMessage() (string, bool) }:
+			next.Message, next.Public = e.publicMessage()
+		default:
+			if be, ok := err.(BaseError); ok {
+				next.Message = be.String()
+			} else {
+				next.Message = err.Error() //includes wrapped errors
+				return append(frames, next)
+			}
+		}
+		if ce, ok := err.(interface{ contextValues() []ContextValue }); ok {
+			for _, v := range ce.contextValues() {
+				if next.Context == nil {
+					next.Context = map[string]string{}
+				}
+				next.Context[v.Name] = v.Value
+			}
+		}
+		frames = append(frames, next)
+		next = Frame{}
+		if u, ok := err.(interface{ Unwrap() error }); ok {
+			err = u.Unwrap()
+		} else {
+			err = nil
+		}
+	}
+	if next.Code != 0 || next.Kind != "" || next.RetryAt != nil || next.Permanent || next.Ambiguous || next.Idempotent {
+		frames = append(frames, next)
+	}
+	return frames
+} //Frames()
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrames(t *testing.T) {
+	_, origErr := os.Open("/some/file/that/does/not/exist")
+	expLine := GetCaller(1).Line() + 1
+	err := Wrap(origErr, "cannot load config")
+	err = Code(WrapPublicf(err, "cannot create user(%s)", Secret("jan")), 500)
+	err = WrapField(err, "user")
+
+	frames := Frames(err)
+	assert.Equal(t, []Frame{
+		{Message: "invalid user", Public: true, Field: "user", Source: "github.com/go-msvc/errors/v2/frames_test.go(" + strconv.Itoa(expLine+2) + ")", Function: "TestFrames"},
+		{Message: "cannot create user(***)", Public: true, Code: 500, Source: "github.com/go-msvc/errors/v2/frames_test.go(" + strconv.Itoa(expLine+1) + ")", Function: "TestFrames"},
+		{Message: "cannot load config", Source: "github.com/go-msvc/errors/v2/frames_test.go(" + strconv.Itoa(expLine) + ")", Function: "TestFrames"},
+		{Message: origErr.Error()},
+	}, frames)
+
+	//wrappers in another function and on another line do not change the source of the message
+	msgErr := Errorf("not found")
+	msgLine := GetCaller(1).Line() - 1
+	frames = Frames(classify(msgErr))
+	assert.Equal(t, []Frame{
+		{Message: "not found", Code: 404, Kind: "NotFound", Permanent: true, Source: "github.com/go-msvc/errors/v2/frames_test.go(" + strconv.Itoa(msgLine) + ")", Function: "TestFrames"},
+	}, frames)
+
+	//retry time and code in the same frame
+	at := time.Now().Add(time.Hour)
+	frames = Frames(Retry(Codef(503, "busy"), time.Hour))
+	assert.Len(t, frames, 1)
+	assert.Equal(t, "busy", frames[0].Message)
+	assert.Equal(t, 503, frames[0].Code)
+	assert.WithinDuration(t, at, *frames[0].RetryAt, time.Second)
+
+	//joined
+	frames = Frames(Wrap(join(GetCaller(1), Error("one"), Wrap(Error("two"), "three")), "many"))
+	assert.Len(t, frames, 2)
+	assert.Equal(t, "many", frames[0].Message)
+	assert.Equal(t, [][]Frame{
+		{{Message: "one", Source: frames[1].Joined[0][0].Source, Function: "TestFrames"}},
+		{
+			{Message: "three", Source: frames[1].Joined[1][0].Source, Function: "TestFrames"},
+			{Message: "two", Source: frames[1].Joined[1][1].Source, Function: "TestFrames"},
+		},
+	}, frames[1].Joined)
+
+	//json
+	data, _ := json.Marshal(Frames(Codef(404, "not found")))
+	assert.Contains(t, string(data), `"msg":"not found"`)
+	assert.Contains(t, string(data), `"code":404`)
+}
+
+func classify(err error) error {
+	err = WithKind(err, KindNotFound)
+	return Permanent(
+		Code(err, 404))
+}
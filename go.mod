@@ -2,9 +2,7 @@ module github.com/go-msvc/errors/v2
 
 go 1.24
 
-require (
-	github.com/stretchr/testify v1.10.0
-)
+require github.com/stretchr/testify v1.10.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
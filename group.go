@@ -0,0 +1,147 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Group runs goroutines and collects their errors, like golang.org/x/sync/errgroup,
+// but each error is wrapped with the name of the goroutine and the source where it was started,
+// panics are returned as errors, and Wait() returns all the errors, see NewGroup()
+type Group struct {
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	cancelOn func(error) bool
+	wg       sync.WaitGroup
+	mutex    sync.Mutex
+	errs     []error //in the order that goroutines were started
+	stopped  bool    //true when the group cancelled the context
+}
+
+// GroupOption changes the defaults of NewGroup()
+type GroupOption func(*Group)
+
+// WithCancelOn() sets which errors cancel the context of the group,
+// the default is errors that should not be retried, see ShouldRetry(),
+// and nil never cancels it
+func WithCancelOn(fn func(err error) bool) GroupOption {
+	return func(g *Group) {
+		g.cancelOn = fn
+	}
+}
+
+// NewGroup() returns a Group and a context derived from ctx for its goroutines,
+// which is cancelled when a goroutine fails with an error that should not be retried (see WithCancelOn())
+// or when Wait() returns
+func NewGroup(ctx context.Context, opts ...GroupOption) (*Group, context.Context) {
+	g := &Group{
+		cancelOn: func(err error) bool {
+			_, retry := ShouldRetry(err)
+			return !retry
+		},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.ctx, g.cancel = context.WithCancelCause(ctx)
+	return g, g.ctx
+}
+
+// Go() calls fn in a goroutine with the context of the group.
+// Its error is wrapped with the name, like "load users because ...", and the source where Go() was called.
+// A panic in fn is returned as an error.
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	source := GetCaller(2)
+	g.mutex.Lock()
+	i := len(g.errs)
+	g.errs = append(g.errs, nil)
+	g.mutex.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		err := run(g.ctx, fn)
+		if err == nil {
+			return
+		}
+		err = &msgError{
+			baseError: baseError{
+				wrapped: err,
+				source:  source,
+			},
+			msg: name,
+		}
+		g.mutex.Lock()
+		defer g.mutex.Unlock()
+		if g.stopped && errors.Is(err, context.Canceled) {
+			return //only cancelled because another goroutine failed
+		}
+		g.errs[i] = err
+		if !g.stopped && g.cancelOn != nil && g.cancelOn(err) {
+			g.stopped = true
+			g.cancel(err)
+		}
+	}()
+} //Group.Go()
+
+// run calls fn and returns a panic as an error
+func run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			source := panicSource()
+			if rerr, ok := r.(error); ok {
+				err = formatted(source, nil, "panic: %w", rerr)
+			} else {
+				err = formatted(source, nil, "panic: %v", r)
+			}
+		}
+	}()
+	return fn(ctx)
+}
+
+// panicSource returns where a recovered panic happened, which is the first frame after the deferred func in run()
+// that is not in the runtime, like runtime.gopanic() and runtime.sigpanic(), and not run() itself when fn is nil
+func panicSource() Caller {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs) //skip runtime.Callers() and panicSource()
+	frames := runtime.CallersFrames(pcs[:n])
+	deferred, more := frames.Next()
+	runFunc := strings.TrimSuffix(deferred.Function, ".func1")
+	for more {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") && frame.Function != runFunc {
+			return caller{file: frame.File, line: frame.Line, pkgDotFunc: frame.Function}
+		}
+	}
+	return caller{line: -1}
+}
+
+// Wait() waits for all goroutines to return, cancels the context,
+// and returns nil when none of them failed, else the error,
+// or the errors joined in the order of Go() calls, which reads as a tree with "%-v":
+//
+//	fmt.Printf("%-v\n", g.Wait()) //writes:
+//	- main.go(20):load users
+//	  users.go(42):cannot connect to database
+//	- main.go(21):load orders
+//	  orders.go(17):panic: runtime error: index out of range [3] with length 3
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(nil)
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	list := []error{}
+	for _, err := range g.errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+	if len(list) == 1 {
+		return list[0]
+	}
+	return join(GetCaller(2), list...)
+}
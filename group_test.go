@@ -0,0 +1,134 @@
+package errors_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/go-msvc/errors/v2/errtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	g, ctx := errors.NewGroup(context.Background())
+	g.Go("load users", func(ctx context.Context) error {
+		return nil
+	})
+	g.Go("load orders", func(ctx context.Context) error {
+		return errors.Retry(errors.Error("database busy"), time.Second)
+	})
+	g.Go("load items", func(ctx context.Context) error {
+		var m map[string]int
+		m["x"] = 1 //panics
+		return nil
+	})
+	err := g.Wait()
+	assert.Error(t, ctx.Err(), "cancelled by Wait()")
+
+	//sources of the Go() calls and of the panic, with the runtime error wrapped with %w
+	errtest.Golden(t, err)
+	assert.True(t, errors.IsRetryable(err), "the first error that can be found with As()")
+}
+
+func TestGroupCancel(t *testing.T) {
+	g, ctx := errors.NewGroup(context.Background())
+	g.Go("wait", func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.Wrap(ctx.Err(), "cancelled")
+	})
+	g.Go("fail", func(ctx context.Context) error {
+		return errors.Error("failed")
+	})
+	err := g.Wait()
+	errtest.AssertChain(t, err, "fail", "failed") //not the cancelled goroutine
+	errtest.AssertSourceHere(t, err)
+	assert.Equal(t, err, context.Cause(ctx))
+
+	//retryable errors do not cancel
+	g, ctx = errors.NewGroup(context.Background())
+	g.Go("retryable", func(ctx context.Context) error {
+		return errors.Retry(errors.Error("busy"), time.Second)
+	})
+	g.Go("other", func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return ctx.Err()
+	})
+	err = g.Wait()
+	errtest.AssertChain(t, err, "retryable", "busy")
+
+	//never cancel
+	g, _ = errors.NewGroup(context.Background(), errors.WithCancelOn(nil))
+	g.Go("one", func(ctx context.Context) error {
+		return errors.Error("failed")
+	})
+	g.Go("two", func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return ctx.Err()
+	})
+	errtest.AssertChain(t, g.Wait(), "one", "failed") //two was not cancelled
+
+	//all ok
+	g, _ = errors.NewGroup(context.Background())
+	g.Go("ok", func(ctx context.Context) error { return nil })
+	assert.NoError(t, g.Wait())
+}
+
+func TestGroupPanicError(t *testing.T) {
+	g, _ := errors.NewGroup(context.Background())
+	g.Go("panics", func(ctx context.Context) error {
+		panic(errTest)
+	})
+	err := g.Wait()
+	errtest.AssertIs(t, err, errTest)
+	errtest.AssertChain(t, err, "panics", "panic: test", "test")
+}
+
+var errTest = errors.Error("test")
+
+func TestGroupPanicSource(t *testing.T) {
+	for name, fn := range map[string]func(ctx context.Context) error{
+		"panic":        panicExplicit,
+		"index":        panicIndex,
+		"nil pointer":  panicNilPointer,
+		"nil map":      panicNilMap,
+		"nil function": nil,
+	} {
+		g, _ := errors.NewGroup(context.Background())
+		g.Go(name, fn)
+		err := errors.Unwrap(g.Wait()) //the panic, wrapped with the name
+		if !assert.Implements(t, (*errors.BaseError)(nil), err, name) {
+			continue
+		}
+		source := err.(errors.BaseError).Source()
+		if fn == nil {
+			assert.Equal(t, "group.go", filepath.Base(source.File()), name) //where the group called it
+			continue
+		}
+		assert.Equal(t, "group_test.go", filepath.Base(source.File()), name)
+		assert.True(t, strings.HasPrefix(source.Function(), "panic"), "%s: %s", name, source.Function())
+	}
+}
+
+func panicExplicit(ctx context.Context) error {
+	panic("boom")
+}
+
+func panicIndex(ctx context.Context) error {
+	list := []int{1, 2, 3}
+	i := len(list)
+	return errors.Errorf("%d", list[i])
+}
+
+func panicNilPointer(ctx context.Context) error {
+	var p *time.Time
+	return errors.Errorf("%d", p.Year())
+}
+
+func panicNilMap(ctx context.Context) error {
+	var m map[string]int
+	m["x"] = 1
+	return nil
+}
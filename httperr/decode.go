@@ -0,0 +1,180 @@
+package httperr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+// Method() returns a coded 405 error when the request method is not one of the expected methods
+func Method(httpReq *http.Request, methods ...string) error {
+	for _, method := range methods {
+		if httpReq.Method == method {
+			return nil
+		}
+	}
+	return errors.Code(errors.Publicf("method %s not allowed, expected %s", httpReq.Method, strings.Join(methods, " or ")), http.StatusMethodNotAllowed)
+}
+
+// DefaultMaxBytes is the size limit of the request body in Decode() and DecodeStrict(), see WithMaxBytes()
+const DefaultMaxBytes = 1 << 20
+
+// DecodeOption changes the defaults of Decode() and DecodeStrict()
+type DecodeOption func(*decoder)
+
+// WithMaxBytes() sets the size limit of the request body,
+// larger bodies fail with a coded 413 (Request Entity Too Large) error
+func WithMaxBytes(n int64) DecodeOption {
+	return func(d *decoder) {
+		d.maxBytes = n
+	}
+}
+
+type decoder struct {
+	maxBytes int64
+	strict   bool
+}
+
+// Decode() parses the JSON request body into req and then validates it
+// when req implements errors.ValidatorContext (called with the request context) or errors.Validator.
+// All errors are coded 400 (Bad Request) unless the validator returned its own code or the body is too large,
+// and JSON errors refer to the field path and line/column in the body so the user knows what to fix.
+func Decode(httpReq *http.Request, req any, opts ...DecodeOption) error {
+	return decode(httpReq, req, false, opts)
+}
+
+// DecodeStrict() is like Decode() but also rejects fields in the JSON body that are not defined in req,
+// suggesting the closest known json name, e.g. "unknown field 'adress' at line 1 column 2, did you mean 'address'?"
+// for the body {"adress":{}}, where the column is that of the quote before the key.
+func DecodeStrict(httpReq *http.Request, req any, opts ...DecodeOption) error {
+	return decode(httpReq, req, true, opts)
+}
+
+func decode(httpReq *http.Request, req any, strict bool, opts []DecodeOption) error {
+	source := errors.GetCaller(3) //the caller of Decode() or DecodeStrict(), to see which handler failed
+	d := decoder{
+		maxBytes: DefaultMaxBytes,
+		strict:   strict,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	if httpReq.Body == nil || httpReq.Body == http.NoBody {
+		return errors.Code(errors.Public("missing body"), http.StatusBadRequest)
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(nil, httpReq.Body, d.maxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return errors.Code(errors.WrapPublicf(err, "body larger than %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+		}
+		return errors.Code(errors.WrapPublic(err, "cannot read body"), http.StatusBadRequest)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.Code(errors.Public("missing body"), http.StatusBadRequest)
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return errors.Code(errors.WrapPublic(jsonError(data, err), "cannot parse JSON body"), http.StatusBadRequest)
+	}
+	if d.strict {
+		if err := unknownFields(data, reflect.TypeOf(req)); err != nil {
+			return errors.Code(errors.WrapPublic(err, "cannot parse JSON body"), http.StatusBadRequest)
+		}
+	}
+
+	switch v := req.(type) {
+	case errors.ValidatorContext:
+		err = v.Validate(httpReq.Context())
+	case errors.Validator:
+		err = v.Validate()
+	}
+	if err != nil {
+		err = errors.WrapPublicAt(source, err, "invalid request")
+		if !errors.HasCode(err) {
+			err = errors.Code(err, http.StatusBadRequest)
+		}
+		return err
+	}
+	return nil
+} //decode()
+
+// jsonError describes errors from encoding/json with the field path and position in the data
+func jsonError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := position(data, syntaxErr.Offset)
+		return errors.Publicf("%s at line %d column %d", strings.TrimPrefix(syntaxErr.Error(), "json: "), line, col)
+	case errors.As(err, &typeErr):
+		line, col := position(data, typeErr.Offset)
+		err := errors.Publicf("expected %s instead of %s at line %d column %d", jsonType(typeErr.Type), typeErr.Value, line, col)
+		if typeErr.Field == "" {
+			return err
+		}
+		return errors.WrapField(err, typeErr.Field)
+	}
+	return err
+} //jsonError()
+
+// jsonType returns the JSON word for values of Go type t, like "object" for a struct,
+// so messages do not show Go type names to users
+func jsonType(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "value"
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return "value"
+} //jsonType()
+
+// position returns the 1-based line and column of the byte before offset in data
+func position(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = len(before) - bytes.LastIndexByte(before, '\n') - 1
+	return line, col
+}
+
+// Error() writes err to the response with the status from its code, or else from the kind set with errors.WithKind(),
+// else 500 (Internal Server Error), so that failures like a missing file on the server are not reported as 404.
+// The body only has the public messages, see errors.UserMessage(),
+// or the status text when none of the messages are public.
+func Error(httpRes http.ResponseWriter, err error) {
+	status, ok := errors.GetCode(err)
+	if !ok || status < 100 || status > 599 {
+		status = http.StatusInternalServerError
+		if kind, ok := errors.GetKind(err); ok {
+			status = kind.HTTPStatus()
+		}
+	}
+	msg := errors.UserMessage(err)
+	if msg == "" {
+		msg = http.StatusText(status)
+	}
+	http.Error(httpRes, msg, status)
+}
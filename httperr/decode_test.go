@@ -0,0 +1,121 @@
+package httperr
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	Street string `json:"street"`
+}
+
+type testRequest struct {
+	Name    string       `json:"name"`
+	Address *testAddress `json:"address"`
+}
+
+func (req testRequest) Validate(ctx context.Context) error {
+	if req.Name == "" {
+		return errors.Public("missing name")
+	}
+	if req.Name == "jan" {
+		return errors.Codef(http.StatusConflict, "name already taken")
+	}
+	return nil
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		body       string
+		expCode    int
+		expMessage string
+		expField   string
+	}{
+		{"", 400, "missing body", ""},
+		{`{"name":"piet"}`, 0, "", ""},
+		{`{}`, 400, "invalid request because missing name", ""},
+		{`{"name":"jan"}`, 409, "invalid request because name already taken", ""},
+		{`{"name":1}`, 400, "cannot parse JSON body because invalid name because expected string instead of number at line 1 column 9", "name"},
+		{"{\n\"name\":\"piet\",\n\"address\":{\"street\":44}}", 400, "cannot parse JSON body because invalid address.street because expected string instead of number at line 3 column 22", "address.street"},
+		{`{"name":"piet","address":1}`, 400, "cannot parse JSON body because invalid address because expected object instead of number at line 1 column 26", "address"},
+		{"{\n\"name\":\"piet\"\n\"address\":{}}", 400, "cannot parse JSON body because invalid character '\"' after object key:value pair at line 3 column 1", ""},
+		{`{"name":"piet"`, 400, "cannot parse JSON body because unexpected end of JSON input at line 1 column 14", ""},
+	}
+	for index, test := range tests {
+		t.Run(fmt.Sprintf("test[%d]:%s", index, test.body), func(t *testing.T) {
+			httpReq := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			var req testRequest
+			err := Decode(httpReq, &req)
+			if test.expCode == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			code, _ := errors.GetCode(err)
+			assert.Equal(t, test.expCode, code)
+			assert.Equal(t, test.expMessage, fmt.Sprintf("%+s", err))
+			assert.Equal(t, test.expField, errors.FieldPath(err))
+		})
+	}
+}
+
+func TestDecodeSource(t *testing.T) {
+	var req testRequest
+	err := Decode(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)), &req)
+	assert.Equal(t, fmt.Sprintf("decode_test.go(%d):invalid request", errors.GetCaller(1).Line()-1), fmt.Sprintf("%v", err)) //not in decode.go
+}
+
+func TestDecodeMaxBytes(t *testing.T) {
+	body := `{"name":"piet"}`
+	var req testRequest
+	assert.Nil(t, Decode(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), &req, WithMaxBytes(int64(len(body)))))
+
+	err := Decode(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), &req, WithMaxBytes(10))
+	code, _ := errors.GetCode(err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	assert.Equal(t, "body larger than 10 bytes", errors.UserMessage(err))
+
+	//default limit
+	err = DecodeStrict(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"`+strings.Repeat("x", DefaultMaxBytes)+`"}`)), &req)
+	code, _ = errors.GetCode(err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+}
+
+func TestMethod(t *testing.T) {
+	assert.Nil(t, Method(httptest.NewRequest(http.MethodPut, "/", nil), http.MethodPost, http.MethodPut))
+	err := Method(httptest.NewRequest(http.MethodGet, "/", nil), http.MethodPost, http.MethodPut)
+	code, _ := errors.GetCode(err)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	assert.Equal(t, "method GET not allowed, expected POST or PUT", err.Error())
+}
+
+func TestError(t *testing.T) {
+	httpRes := httptest.NewRecorder()
+	Error(httpRes, errors.Code(errors.WrapPublic(errors.Public("missing name"), "invalid request"), http.StatusBadRequest))
+	assert.Equal(t, http.StatusBadRequest, httpRes.Code)
+	assert.Equal(t, "invalid request because missing name\n", httpRes.Body.String())
+
+	//internal messages are not written to the user
+	httpRes = httptest.NewRecorder()
+	Error(httpRes, errors.Wrap(errors.Error("open /etc/secret: permission denied"), "something broke"))
+	assert.Equal(t, http.StatusInternalServerError, httpRes.Code)
+	assert.Equal(t, "Internal Server Error\n", httpRes.Body.String())
+
+	//status from the kind
+	httpRes = httptest.NewRecorder()
+	Error(httpRes, errors.WithKind(errors.Public("user not found"), errors.KindNotFound))
+	assert.Equal(t, http.StatusNotFound, httpRes.Code)
+	assert.Equal(t, "user not found\n", httpRes.Body.String())
+
+	//kinds that were not set explicitly are not used, e.g. a missing file on the server
+	httpRes = httptest.NewRecorder()
+	Error(httpRes, errors.Wrap(fs.ErrNotExist, "cannot load templates"))
+	assert.Equal(t, http.StatusInternalServerError, httpRes.Code)
+}
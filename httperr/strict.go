@@ -0,0 +1,194 @@
+package httperr
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// unknownFields walks the JSON document in data along with the Go type t
+// and returns an error for the first object key that does not match a field,
+// suggesting the closest known json name, e.g. "unknown field 'adress' at line 1 column 2, did you mean 'address'?"
+// for {"adress":{}}, where the column is that of the quote before the key.
+// It expects data to be valid JSON that was already decoded into t.
+func unknownFields(data []byte, t reflect.Type) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return walk(dec, data, t, "")
+}
+
+func walk(dec *json.Decoder, data []byte, t reflect.Type, path string) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && (reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)) {
+		t = nil //custom decoding, so anything is allowed inside
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		var fields map[string]reflect.Type
+		if t != nil && t.Kind() == reflect.Struct {
+			fields = jsonFields(t, map[string]reflect.Type{})
+		}
+		for dec.More() {
+			offset := dec.InputOffset()
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			var valueType reflect.Type
+			switch {
+			case fields != nil:
+				var ok bool
+				if valueType, ok = field(fields, key); !ok {
+					return unknownField(data, offset, path, key, fields)
+				}
+			case t != nil && t.Kind() == reflect.Map:
+				valueType = t.Elem()
+			}
+			if err := walk(dec, data, valueType, join(path, key)); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token() //'}'
+		return err
+	case json.Delim('['):
+		var elemType reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elemType = t.Elem()
+		}
+		for index := 0; dec.More(); index++ {
+			if err := walk(dec, data, elemType, fmt.Sprintf("%s[%d]", path, index)); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token() //']'
+		return err
+	}
+	return nil
+} //walk()
+
+// field finds the field for key like encoding/json does, preferring an exact match
+// but also accepting a case-insensitive match
+func field(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// jsonFields returns the json names of the fields in struct type t,
+// including fields promoted from embedded structs without a json name
+func jsonFields(t reflect.Type, fields map[string]reflect.Type) map[string]reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				jsonFields(ft, fields)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func unknownField(data []byte, offset int64, path, key string, fields map[string]reflect.Type) error {
+	//offset is before the separator and white space that precede the key
+	for offset < int64(len(data)) && data[offset] != '"' {
+		offset++
+	}
+	line, col := position(data, offset+1)
+	var err error
+	if suggestion := closest(key, fields); suggestion != "" {
+		err = errors.Publicf("unknown field '%s' at line %d column %d, did you mean '%s'?", key, line, col, suggestion)
+	} else {
+		err = errors.Publicf("unknown field '%s' at line %d column %d", key, line, col)
+	}
+	if path == "" {
+		return err
+	}
+	return errors.WrapField(err, path)
+}
+
+// closest returns the field name with the smallest edit distance to key,
+// or "" when none is close enough to be a likely misspelling
+func closest(key string, fields map[string]reflect.Type) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names) //so that ties are resolved the same every time
+	best, bestDistance := "", max(2, len(key)/3)+1
+	for _, name := range names {
+		if d := distance(strings.ToLower(key), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// distance is the Levenshtein edit distance between a and b
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
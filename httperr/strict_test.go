@@ -0,0 +1,65 @@
+package httperr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testStrictRequest struct {
+	testBase
+	Name     string            `json:"name"`
+	Address  testAddress       `json:"address"`
+	Contacts []testAddress     `json:"contacts"`
+	Labels   map[string]string `json:"labels"`
+	Internal string            `json:"-"`
+	Nickname string
+}
+
+func TestDecodeStrict(t *testing.T) {
+	tests := []struct {
+		body       string
+		expMessage string
+		expField   string
+	}{
+		{`{"id":"1","name":"jan","address":{"street":"x"},"contacts":[{"street":"y"}],"labels":{"any":"thing"},"nickname":"j"}`, "", ""},
+		{`{"Name":"jan","ADDRESS":{"Street":"x"}}`, "", ""}, //case-insensitive like encoding/json
+		{`{"adress":{}}`, "cannot parse JSON body because unknown field 'adress' at line 1 column 2, did you mean 'address'?", ""},
+		{`{"nmae":"jan"}`, "cannot parse JSON body because unknown field 'nmae' at line 1 column 2, did you mean 'name'?", ""},
+		{`{"name":"jan", "adress":{}}`, "cannot parse JSON body because unknown field 'adress' at line 1 column 16, did you mean 'address'?", ""},
+		{"{\n  \"address\": {\"stret\":\"x\"}}", "cannot parse JSON body because invalid address because unknown field 'stret' at line 2 column 15, did you mean 'street'?", "address"},
+		{`{"contacts":[{"street":"y"},{"town":"z"}]}`, "cannot parse JSON body because invalid contacts[1] because unknown field 'town' at line 1 column 30", "contacts[1]"},
+		{`{"internal":"x"}`, "cannot parse JSON body because unknown field 'internal' at line 1 column 2", ""},
+	}
+	for index, test := range tests {
+		t.Run(fmt.Sprintf("test[%d]:%s", index, test.body), func(t *testing.T) {
+			httpReq := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			var req testStrictRequest
+			err := DecodeStrict(httpReq, &req)
+			if test.expMessage == "" {
+				assert.Nil(t, err)
+				return
+			}
+			code, _ := errors.GetCode(err)
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.Equal(t, test.expMessage, fmt.Sprintf("%+s", err))
+			assert.Equal(t, test.expField, errors.FieldPath(err))
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, distance("address", "address"))
+	assert.Equal(t, 1, distance("adress", "address"))
+	assert.Equal(t, 2, distance("nmae", "name"))
+	assert.Equal(t, 3, distance("", "abc"))
+}
@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// joinedError is a list of errors returned as one, like the standard errors.Join(),
+// so errors.Is() and errors.As() still find each of them,
+// but it also records the source reference where the errors were joined
+// and formats the list with the same verbs and flags as the other errors in this package.
+type joinedError struct {
+	baseError
+	errs   []error
+	counts []int //when not nil, the number of times each error occurred, see Collector
+	more   int   //number of errors not in the list, see Collector
+}
+
+// join returns nil when there are no errors, else a joinedError with nil errors removed
+func join(source Caller, errs ...error) BaseError {
+	list := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return joinedError{
+		baseError: baseError{
+			source: source,
+		},
+		errs: list,
+	}
+}
+
+func (err joinedError) Unwrap() []error {
+	return err.errs
+}
+
+// return the errors in the list, each recursing into its wrapped errors, separated with "; "
+func (err joinedError) String() string {
+	s := make([]string, len(err.errs))
+	for i, e := range err.errs {
+		s[i] = e.Error() + err.repeats(i)
+	}
+	if err.more > 0 {
+		s = append(s, err.andMore())
+	}
+	return strings.Join(s, "; ")
+}
+
+// repeats returns the number of times error i occurred, like " (x37)", or "" when only once
+func (err joinedError) repeats(i int) string {
+	if i < len(err.counts) && err.counts[i] > 1 {
+		return " (x" + strconv.Itoa(err.counts[i]) + ")"
+	}
+	return ""
+}
+
+// andMore returns the number of errors not in the list, like "and 9,812 more"
+func (err joinedError) andMore() string {
+	digits := strconv.Itoa(err.more)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return "and " + digits + " more"
+}
+
+func (err joinedError) Error() string {
+	return err.String()
+}
+
+// called when formatting the err with fmt.Printf() like functions
+// without flags it writes the list like Error(),
+// with '+' each error is formatted with the same verb and flag separated with "; ",
+// with '-' each error is written as an item of a multi-line list, indented under the item marker
+func (err joinedError) Format(f fmt.State, c rune) {
+	unsafe := ""
+	if f.Flag('#') {
+		unsafe = "#" //show secrets
+	}
+	switch {
+	case c == 'u':
+		io.WriteString(f, userMessage(err, userLink(f)))
+	case f.Flag('-'):
+		for i, e := range err.errs {
+			if i > 0 {
+				io.WriteString(f, "\n")
+			}
+			lines := strings.Split(formatWith(e, "-"+unsafe, c), "\n")
+			lines[len(lines)-1] += err.repeats(i) //after the whole error, like Error()
+			io.WriteString(f, "- "+strings.Join(lines, "\n  "))
+		}
+		if err.more > 0 {
+			if len(err.errs) > 0 {
+				io.WriteString(f, "\n")
+			}
+			io.WriteString(f, "- "+err.andMore())
+		}
+	case f.Flag('+'):
+		for i, e := range err.errs {
+			if i > 0 {
+				io.WriteString(f, "; ")
+			}
+			io.WriteString(f, formatWith(e, "+"+unsafe, c)+err.repeats(i))
+		}
+		if err.more > 0 {
+			if len(err.errs) > 0 {
+				io.WriteString(f, "; ")
+			}
+			io.WriteString(f, err.andMore())
+		}
+	default:
+		switch c {
+		case 'v':
+			fmt.Fprintf(f, "%s:%s", err.source, err.String()) //source with "%s" -> basename
+		case 'V':
+			fmt.Fprintf(f, "%v:%s", err.source, err.String()) //source with "%v" -> fullpath
+		default:
+			io.WriteString(f, err.String())
+		}
+	}
+} //joinedError.Format()
+
+// formatWith formats err with the flags and verb when it implements fmt.Formatter,
+// else it returns err.Error() so that errors from other packages are not garbled by verbs like %V
+func formatWith(err error, flags string, c rune) string {
+	if _, ok := err.(fmt.Formatter); ok {
+		return fmt.Sprintf("%"+flags+string(c), err)
+	}
+	return err.Error()
+}
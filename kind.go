@@ -0,0 +1,203 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+)
+
+// Kind is the category of a failure, for domain code that should not choose HTTP or gRPC codes,
+// see WithKind() and KindOf()
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindNotFound
+	KindAlreadyExists
+	KindInvalid
+	KindUnauthenticated
+	KindPermissionDenied
+	KindConflict
+	KindTimeout
+	KindUnavailable
+	KindInternal
+	KindCanceled
+	KindRateLimited
+	KindUnimplemented
+)
+
+var kindNames = []string{"Unknown", "NotFound", "AlreadyExists", "Invalid", "Unauthenticated", "PermissionDenied",
+	"Conflict", "Timeout", "Unavailable", "Internal", "Canceled", "RateLimited", "Unimplemented"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "Unknown"
+	}
+	return kindNames[k]
+}
+
+// HTTPStatus() returns the default HTTP status code of the kind
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindNotFound:
+		return 404
+	case KindAlreadyExists, KindConflict:
+		return 409
+	case KindInvalid:
+		return 400
+	case KindUnauthenticated:
+		return 401
+	case KindPermissionDenied:
+		return 403
+	case KindTimeout:
+		return 504
+	case KindUnavailable:
+		return 503
+	case KindCanceled:
+		return 499 //client closed request
+	case KindRateLimited:
+		return 429
+	case KindUnimplemented:
+		return 501
+	default:
+		return 500
+	}
+}
+
+// GRPCCode() returns the default gRPC status code of the kind,
+// which converts to google.golang.org/grpc/codes.Code
+func (k Kind) GRPCCode() uint32 {
+	switch k {
+	case KindCanceled:
+		return 1
+	case KindInvalid:
+		return 3
+	case KindTimeout:
+		return 4
+	case KindNotFound:
+		return 5
+	case KindAlreadyExists:
+		return 6
+	case KindPermissionDenied:
+		return 7
+	case KindRateLimited:
+		return 8
+	case KindConflict:
+		return 10 //aborted
+	case KindUnimplemented:
+		return 12
+	case KindInternal:
+		return 13
+	case KindUnavailable:
+		return 14
+	case KindUnauthenticated:
+		return 16
+	default:
+		return 2 //unknown
+	}
+}
+
+// kindOfStatus returns the kind of an HTTP status code
+func kindOfStatus(status int) Kind {
+	switch status {
+	case 400, 422:
+		return KindInvalid
+	case 401:
+		return KindUnauthenticated
+	case 403:
+		return KindPermissionDenied
+	case 404, 410:
+		return KindNotFound
+	case 408, 504:
+		return KindTimeout
+	case 409:
+		return KindConflict
+	case 429:
+		return KindRateLimited
+	case 499:
+		return KindCanceled
+	case 501:
+		return KindUnimplemented
+	case 502, 503:
+		return KindUnavailable
+	}
+	switch {
+	case status >= 500 && status < 600:
+		return KindInternal
+	case status >= 400 && status < 500:
+		return KindInvalid
+	}
+	return KindUnknown
+}
+
+// WithKind() wraps err with the kind of failure, see KindOf()
+func WithKind(err error, kind Kind) BaseError {
+	if err == nil {
+		return nil
+	}
+	return kindError{
+		baseError: baseError{
+			wrapped: err,
+			source:  GetCaller(2),
+		},
+		kind: kind,
+	}
+}
+
+// GetKind() returns the outermost kind set with WithKind() in err,
+// without guessing it from codes or standard errors like KindOf() does
+func GetKind(err error) (kind Kind, ok bool) {
+	var ke kindError
+	if errors.As(err, &ke) {
+		return ke.kind, true
+	}
+	return kind, false
+}
+
+// KindOf() returns the outermost kind set with WithKind() in err,
+// else the kind of the outermost code, see GetCode(),
+// else the kind of standard errors like fs.ErrNotExist, sql.ErrNoRows, context.DeadlineExceeded
+// and network timeouts, else KindUnknown.
+func KindOf(err error) Kind {
+	if err == nil {
+		return KindUnknown
+	}
+	if kind, ok := GetKind(err); ok {
+		return kind
+	}
+	if code, ok := GetCode(err); ok {
+		if kind := kindOfStatus(code); kind != KindUnknown {
+			return kind
+		}
+	}
+	var timeout interface{ Timeout() bool }
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, sql.ErrNoRows):
+		return KindNotFound
+	case errors.Is(err, fs.ErrExist):
+		return KindAlreadyExists
+	case errors.Is(err, fs.ErrPermission):
+		return KindPermissionDenied
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	case errors.As(err, &timeout) && timeout.Timeout():
+		return KindTimeout //like net.Error
+	}
+	return KindUnknown
+} //KindOf()
+
+type kindError struct {
+	baseError
+	kind Kind
+}
+
+func (err kindError) Kind() Kind {
+	return err.kind
+}
+
+func (err kindError) String() string {
+	return err.kind.String()
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKind(t *testing.T) {
+	err := WithKind(Error("user not found"), KindNotFound)
+	assert.Equal(t, KindNotFound, KindOf(err))
+	assert.Equal(t, KindNotFound, KindOf(Wrap(err, "cannot get user")))
+	assert.Equal(t, "user not found", err.Error())
+	assert.Equal(t, "user not found", fmt.Sprintf("%+s", err))
+	assert.Equal(t, KindConflict, KindOf(WithKind(Wrap(err, "cannot update"), KindConflict)), "outermost kind")
+	assert.Nil(t, WithKind(nil, KindNotFound))
+
+	kind, ok := GetKind(Wrap(err, "cannot get user"))
+	assert.True(t, ok)
+	assert.Equal(t, KindNotFound, kind)
+	_, ok = GetKind(Code(Error("not found"), 404))
+	assert.False(t, ok, "only kinds set with WithKind()")
+
+	frames := Frames(Wrap(err, "cannot get user"))
+	assert.Equal(t, "", frames[0].Kind)
+	assert.Equal(t, "NotFound", frames[1].Kind)
+	assert.NotEqual(t, Fingerprint(err), Fingerprint(WithKind(Error("user not found"), KindInternal)))
+}
+
+func TestKindOf(t *testing.T) {
+	_, notExist := os.Open("/some/file/that/does/not/exist")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, test := range []struct {
+		err  error
+		kind Kind
+	}{
+		{nil, KindUnknown},
+		{Error("something"), KindUnknown},
+		{Wrap(notExist, "cannot load"), KindNotFound},
+		{Wrapf(sql.ErrNoRows, "user %d", 1), KindNotFound},
+		{os.ErrExist, KindAlreadyExists},
+		{Wrap(os.ErrPermission, "cannot write"), KindPermissionDenied},
+		{Wrap(context.DeadlineExceeded, "cannot call"), KindTimeout},
+		{ctx.Err(), KindCanceled},
+		{Wrap(&net.DNSError{Err: "timeout", IsTimeout: true}, "cannot resolve"), KindTimeout},
+		{&net.DNSError{Err: "no such host", IsNotFound: true}, KindUnknown},
+		{Codef(404, "not found"), KindNotFound},
+		{Codef(418, "teapot"), KindInvalid},
+		{Codef(502, "bad gateway"), KindUnavailable},
+		{Codef(599, "other"), KindInternal},
+		{Code(notExist, 500), KindInternal},
+		{Code(notExist, 200), KindNotFound},
+	} {
+		assert.Equal(t, test.kind, KindOf(test.err), "%+v", test.err)
+	}
+}
+
+func TestKindCodes(t *testing.T) {
+	for kind := KindUnknown; kind <= KindUnimplemented; kind++ {
+		if kind != KindUnknown {
+			assert.NotEqual(t, "Unknown", kind.String())
+		}
+		if kind != KindUnknown && kind != KindAlreadyExists {
+			assert.Equal(t, kind, kindOfStatus(kind.HTTPStatus()), kind.String())
+		}
+	}
+	assert.Equal(t, "Unknown", Kind(99).String())
+	assert.Equal(t, 500, Kind(99).HTTPStatus())
+	assert.Equal(t, uint32(5), KindNotFound.GRPCCode())
+	assert.Equal(t, uint32(2), KindUnknown.GRPCCode())
+	assert.Equal(t, uint32(13), KindInternal.GRPCCode())
+}
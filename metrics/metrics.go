@@ -0,0 +1,197 @@
+// Package metrics counts errors by code, retryability and the package and function where
+// the outermost error was created, for dashboards of error rates per endpoint.
+// The counts are served in the Prometheus text format and as an expvar.
+package metrics
+
+import (
+	"cmp"
+	"expvar"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+// Default is the registry used by Record() and Handler()
+var Default = New()
+
+// Record() counts err in the Default registry
+func Record(err error) {
+	Default.Record(err)
+}
+
+// Handler() serves the Default registry in the Prometheus text format
+func Handler() http.Handler {
+	return Default
+}
+
+// Option changes the defaults of New()
+type Option func(*Registry)
+
+// WithMaxSites() sets the most package and function label pairs (default 200),
+// after which errors from new sites are counted with package and function "other",
+// so the number of series stays bounded.
+// Errors without a source, counted with package and function "none", are not limited.
+func WithMaxSites(n int) Option {
+	return func(r *Registry) {
+		r.maxSites = n
+	}
+}
+
+// WithName() sets the metric name (default "errors_total")
+func WithName(name string) Option {
+	return func(r *Registry) {
+		r.name = name
+	}
+}
+
+// Registry holds error counts
+type Registry struct {
+	name     string
+	maxSites int
+	mutex    sync.RWMutex
+	counts   map[key]*atomic.Int64 //by labels
+	lookup   map[key]*atomic.Int64 //by the key of the error, which has site "other" in counts when over the limit
+	sites    map[site]bool
+}
+
+func New(opts ...Option) *Registry {
+	r := &Registry{
+		name:     "errors_total",
+		maxSites: 200,
+		counts:   map[key]*atomic.Int64{},
+		lookup:   map[key]*atomic.Int64{},
+		sites:    map[site]bool{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+type site struct {
+	pkg      string
+	function string
+}
+
+type key struct {
+	site
+	code      string
+	retryable bool
+}
+
+// label used for errors without a code, and from packages that do not record the source
+const none = "none"
+
+// label used for sites after the limit was reached
+const other = "other"
+
+// Record() counts err, nil is ignored
+func (r *Registry) Record(err error) {
+	if err == nil {
+		return
+	}
+	k := key{
+		site:      site{pkg: none, function: none},
+		code:      none,
+		retryable: errors.IsRetryable(err),
+	}
+	if code, ok := errors.GetCode(err); ok {
+		k.code = strconv.Itoa(code)
+	}
+	if be, ok := err.(errors.BaseError); ok {
+		k.site = site{pkg: be.Source().Package(), function: be.Source().Function()}
+	}
+
+	r.mutex.RLock()
+	count, ok := r.lookup[k]
+	r.mutex.RUnlock()
+	if !ok {
+		r.mutex.Lock()
+		if count, ok = r.lookup[k]; !ok {
+			labels := k
+			if !r.sites[k.site] && k.site != (site{pkg: none, function: none}) {
+				if len(r.sites) < r.maxSites {
+					r.sites[k.site] = true
+				} else {
+					labels.site = site{pkg: other, function: other}
+				}
+			}
+			if count, ok = r.counts[labels]; !ok {
+				count = &atomic.Int64{}
+				r.counts[labels] = count
+			}
+			r.lookup[k] = count
+		}
+		r.mutex.Unlock()
+	}
+	count.Add(1)
+} //Registry.Record()
+
+// Count is the number of errors with the same labels
+type Count struct {
+	Code      string `json:"code"`
+	Retryable bool   `json:"retryable"`
+	Package   string `json:"package"`
+	Function  string `json:"function"`
+	Count     int64  `json:"count"`
+}
+
+// Counts() returns the counts sorted by package, function, code and retryable
+func (r *Registry) Counts() []Count {
+	r.mutex.RLock()
+	counts := make([]Count, 0, len(r.counts))
+	for k, count := range r.counts {
+		counts = append(counts, Count{
+			Code:      k.code,
+			Retryable: k.retryable,
+			Package:   k.pkg,
+			Function:  k.function,
+			Count:     count.Load(),
+		})
+	}
+	r.mutex.RUnlock()
+	slices.SortFunc(counts, func(a, b Count) int {
+		return cmp.Or(
+			cmp.Compare(a.Package, b.Package),
+			cmp.Compare(a.Function, b.Function),
+			cmp.Compare(a.Code, b.Code),
+			cmp.Compare(strconv.FormatBool(a.Retryable), strconv.FormatBool(b.Retryable)),
+		)
+	})
+	return counts
+}
+
+// Var() returns the counts as an expvar, to publish it with:
+//
+//	expvar.Publish("errors", metrics.Default.Var())
+func (r *Registry) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return r.Counts()
+	})
+}
+
+// ServeHTTP() writes the counts in the Prometheus text format, like:
+//
+//	errors_total{code="404",retryable="false",package="github.com/my/pkg",function="GetUser"} 3
+func (r *Registry) ServeHTTP(w http.ResponseWriter, httpReq *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "# HELP %s Number of errors by code, retryable and the package and function where they were created.\n", r.name)
+	fmt.Fprintf(&sb, "# TYPE %s counter\n", r.name)
+	for _, c := range r.Counts() {
+		fmt.Fprintf(&sb, "%s{code=%s,retryable=\"%t\",package=%s,function=%s} %d\n",
+			r.name, label(c.Code), c.Retryable, label(c.Package), label(c.Function), c.Count)
+	}
+	w.Write([]byte(sb.String()))
+}
+
+// label returns the quoted label value with the escapes of the text format
+func label(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
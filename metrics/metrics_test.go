@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func getUser() error {
+	return errors.Codef(404, "user not found")
+}
+
+func connect() error {
+	return errors.Retry(errors.Error("cannot connect"), time.Second)
+}
+
+func TestRecord(t *testing.T) {
+	r := New()
+	r.Record(getUser())
+	r.Record(getUser())
+	r.Record(connect())
+	r.Record(os.ErrNotExist)
+	r.Record(nil)
+
+	pkg := "github.com/go-msvc/errors/v2/metrics"
+	assert.Equal(t, []Count{
+		{Code: "none", Retryable: true, Package: pkg, Function: "connect", Count: 1},
+		{Code: "404", Retryable: false, Package: pkg, Function: "getUser", Count: 2},
+		{Code: "none", Retryable: false, Package: "none", Function: "none", Count: 1},
+	}, r.Counts())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(w.Result().Body)
+	assert.Equal(t, "# HELP errors_total Number of errors by code, retryable and the package and function where they were created.\n"+
+		"# TYPE errors_total counter\n"+
+		`errors_total{code="none",retryable="true",package="`+pkg+`",function="connect"} 1`+"\n"+
+		`errors_total{code="404",retryable="false",package="`+pkg+`",function="getUser"} 2`+"\n"+
+		`errors_total{code="none",retryable="false",package="none",function="none"} 1`+"\n", string(body))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Result().Header.Get("Content-Type"))
+
+	data, err := json.Marshal(r.Counts())
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), r.Var().String())
+}
+
+func TestMaxSites(t *testing.T) {
+	r := New(WithMaxSites(1), WithName("app_errors_total"))
+	r.Record(fmt.Errorf("no site")) //not counted against the limit
+	r.Record(getUser())
+	r.Record(connect())
+	r.Record(connect())
+	r.Record(getUser())
+	counts := r.Counts()
+	if assert.Len(t, counts, 3) {
+		assert.Equal(t, Count{Code: "404", Package: "github.com/go-msvc/errors/v2/metrics", Function: "getUser", Count: 2}, counts[0])
+		assert.Equal(t, Count{Code: "none", Retryable: false, Package: "none", Function: "none", Count: 1}, counts[1])
+		assert.Equal(t, Count{Code: "none", Retryable: true, Package: "other", Function: "other", Count: 2}, counts[2])
+	}
+	assert.Len(t, r.lookup, 3, "sites over the limit are found by their own key")
+}
+
+func TestLabel(t *testing.T) {
+	assert.Equal(t, `"a\\b\"c\nd"`, label("a\\b\"c\nd"))
+}
+
+func BenchmarkRecord(b *testing.B) {
+	r := New()
+	err := getUser()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r.Record(err)
+		}
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // Error(msg) is identical to New(msg)
@@ -29,13 +32,9 @@ func Error(msg string) BaseError {
 }
 
 // Errorf() is the same as Error() and New(), but does message formatting
+// The message is only formatted when first used, so do not change args after creating the error.
 func Errorf(format string, args ...interface{}) BaseError {
-	return &msgError{
-		baseError: baseError{
-			source: GetCaller(2),
-		},
-		msg: fmt.Sprintf(format, args...),
-	}
+	return formatted(GetCaller(2), nil, format, args...)
 }
 
 // Wrap() an existing error with a message and capturing the source where you wrapped
@@ -57,13 +56,34 @@ func Wrapf(err error, format string, args ...interface{}) BaseError {
 	if err == nil {
 		return nil
 	}
-	return &msgError{
-		baseError: baseError{
-			wrapped: err,
-			source:  GetCaller(2),
-		},
-		msg: fmt.Sprintf(format, args...),
+	return formatted(GetCaller(2), err, format, args...)
+}
+
+// Template() returns the format string of the outermost message in err,
+// e.g. "id %s not found" from Errorf("id %s not found", id),
+// or the message itself when it was not formatted, to group errors or translate messages.
+// It returns "" when err has no message from this package.
+func Template(err error) string {
+	var te templatedError
+	if errors.As(err, &te) {
+		format, _ := te.template()
+		return format
 	}
+	return ""
+}
+
+// Args() returns the arguments formatted into the outermost message in err, see Template()
+func Args(err error) []any {
+	var te templatedError
+	if errors.As(err, &te) {
+		_, args := te.template()
+		return args
+	}
+	return nil
+}
+
+type templatedError interface {
+	template() (format string, args []any)
 }
 
 // wrappers around go's default package so you do not need to directly import that too
@@ -86,38 +106,258 @@ func Join(errs ...error) error {
 
 type msgError struct {
 	baseError
-	msg string
+	msg       string         //when not formatted
+	format    string         //when formatted, see Template()
+	args      []any          //when formatted, see Args()
+	formatted *lazyMsg       //when formatted, the message is only formatted when first used
+	inline    []error        //args formatted with %w, which are written in the message and also wrapped
+	joined    bool           //wrapped is the error passed to Wrapf() joined with the inline errors
+	public    bool           //see Public()
+	values    []ContextValue //see WrapCtx()
+}
+
+type lazyMsg struct {
+	once   sync.Once
+	format func() string
+	msg    string
+}
+
+// formatted returns a msgError with a message that is formatted when first used,
+// which saves the cost of errors that are handled without being written.
+// Args formatted with %w are wrapped like fmt.Errorf() does.
+func formatted(source Caller, wrapped error, format string, args ...any) *msgError {
+	err := &msgError{
+		baseError: baseError{
+			wrapped: wrapped,
+			source:  source,
+		},
+		format: format,
+		args:   args,
+		formatted: &lazyMsg{
+			//fmt.Errorf() rather than fmt.Sprintf() so that go vet checks the format and args
+			//of Errorf(), Wrapf() etc. like those of fmt.Errorf(), which allows %w
+			format: func() string { return fmt.Errorf(format, args...).Error() },
+		},
+	}
+	_, wrapArgs := wrapVerbs(format)
+	for _, i := range wrapArgs {
+		if i < len(args) {
+			if w, ok := args[i].(error); ok && w != nil {
+				err.inline = append(err.inline, w)
+			}
+		}
+	}
+	switch {
+	case len(err.inline) == 0:
+	case wrapped == nil && len(err.inline) == 1:
+		err.wrapped = err.inline[0]
+	case wrapped == nil:
+		err.wrapped = join(source, err.inline...)
+	default:
+		err.wrapped = join(source, append([]error{wrapped}, err.inline...)...)
+		err.joined = true
+	}
+	if len(err.inline) > 0 {
+		err.formatted.format = func() string { return err.sprintf(err.args) }
+	}
+	return err
+} //formatted()
+
+// wrapVerbs returns format with %w verbs replaced by %v, and the indexes of args formatted with %w
+func wrapVerbs(format string) (string, []int) {
+	if !strings.Contains(format, "w") {
+		return format, nil
+	}
+	var b []byte
+	var wrapArgs []int
+	argNum := 0
+	argIndex := func(i int) int {
+		//explicit argument index like "[2]"
+		if i < len(format) && format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+					argNum = n - 1
+				}
+				return i + end + 1
+			}
+		}
+		return i
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0; i++ {
+		}
+		i = argIndex(i)
+		if i < len(format) && format[i] == '*' {
+			argNum++
+			i++
+		}
+		for ; i < len(format) && format[i] >= '0' && format[i] <= '9'; i++ {
+		}
+		if i < len(format) && format[i] == '.' {
+			i = argIndex(i + 1)
+			if i < len(format) && format[i] == '*' {
+				argNum++
+				i++
+			}
+			for ; i < len(format) && format[i] >= '0' && format[i] <= '9'; i++ {
+			}
+		}
+		i = argIndex(i)
+		if i >= len(format) || format[i] == '%' {
+			continue
+		}
+		if format[i] == 'w' {
+			if b == nil {
+				b = []byte(format)
+			}
+			b[i] = 'v'
+			wrapArgs = append(wrapArgs, argNum)
+		}
+		argNum++
+	}
+	if b == nil {
+		return format, nil
+	}
+	return string(b), wrapArgs
+} //wrapVerbs()
+
+// sprintf formats the message with args, writing the errors formatted with %w
+// like fmt.Errorf() does, i.e. with Error() rather than their own formatting
+func (err msgError) sprintf(args []any) string {
+	if len(err.inline) == 0 {
+		return fmt.Sprintf(err.format, args...)
+	}
+	format, wrapArgs := wrapVerbs(err.format)
+	args = append([]any{}, args...)
+	for _, i := range wrapArgs {
+		if i < len(args) {
+			if w, ok := args[i].(error); ok && w != nil {
+				args[i] = w.Error()
+			}
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// message returns the message, formatting it if not yet done
+func (err msgError) message() string {
+	if err.formatted == nil {
+		return err.msg
+	}
+	err.formatted.once.Do(func() {
+		err.formatted.msg = err.formatted.format()
+	})
+	return err.formatted.msg
+}
+
+func (err msgError) template() (string, []any) {
+	if err.formatted == nil {
+		return err.msg, nil
+	}
+	return err.format, err.args
+}
+
+// text returns the message, and when unsafe, with the real values of secrets in the args
+func (err msgError) text(unsafe bool) string {
+	if unsafe {
+		if args := unsafeArgs(err.args); args != nil {
+			return err.sprintf(args)
+		}
+	}
+	return err.message()
+}
+
+// next returns the wrapped error that is written after the message,
+// which excludes errors that are already written in the message with %w
+func (err msgError) next() error {
+	if len(err.inline) == 0 {
+		return err.wrapped
+	}
+	if err.joined {
+		return err.wrapped.(joinedError).errs[0] //the error passed to Wrapf() is joined before the inline errors
+	}
+	return nil
+}
+
+// sourced returns the wrapped errors that are written after the message with %v and %V,
+// which are next() and the errors formatted with %w that have their own source reference,
+// while errors from other packages are already written in the message
+func (err msgError) sourced() error {
+	if len(err.inline) == 0 {
+		return err.wrapped
+	}
+	list := []error{}
+	if next := err.next(); next != nil {
+		list = append(list, next)
+	}
+	for _, w := range err.inline {
+		switch w.(type) {
+		case BaseError, fmt.Formatter:
+			list = append(list, w)
+		}
+	}
+	switch len(list) {
+	case 0:
+		return nil
+	case 1:
+		return list[0]
+	}
+	j := err.wrapped.(joinedError) //more than one, so joined in formatted()
+	j.errs = list
+	return j
+}
+
+func (err msgError) contextValues() []ContextValue {
+	return err.values
+}
+
+func (err msgError) publicMessage() (string, bool) {
+	return err.message(), err.public
 }
 
 // return the error string, not recursing into wrapped errors
 func (err msgError) String() string {
-	return err.msg
+	return err.message()
 }
 
 // return the error string, and recurse into wrapped errors
 // to construct a string like abc because def because xyz
 func (err msgError) Error() string {
-	if err.wrapped != nil {
-		return err.String() + " because " + err.wrapped.Error()
+	if next := err.next(); next != nil {
+		return err.String() + " because " + next.Error()
 	}
 	return err.String()
 }
 
 // called when formatting the err with fmt.Printf() like functions
 func (err msgError) Format(f fmt.State, c rune) {
+	if c == 'u' {
+		io.WriteString(f, userMessage(err, userLink(f)))
+		return
+	}
 	var s string
 	//s = fmt.Sprintf("msg(%p)", err)
+	text := err.text(f.Flag('#'))
 	switch c {
 	case 'v':
-		s += fmt.Sprintf("%s:%s", err.source, err.String()) //source with "%s" -> basename
+		s += fmt.Sprintf("%s:%s%s", err.source, text, formatValues(err.values)) //source with "%s" -> basename
 	case 'V':
-		s += fmt.Sprintf("%v:%s", err.source, err.String()) //source with "%v" -> fullpath
+		s += fmt.Sprintf("%v:%s%s", err.source, text, formatValues(err.values)) //source with "%v" -> fullpath
 	default:
-		s += err.String() //no source
+		s += text //no source
 	}
 	io.WriteString(f, s)
 
-	if err.wrapped != nil {
+	//with %s, errors formatted with %w are already in the message,
+	//but with %v and %V those with a source reference are written again to see it
+	wrapped := err.next()
+	if c == 'v' || c == 'V' {
+		wrapped = err.sourced()
+	}
+	if wrapped != nil {
 		recurse := false
 		if f.Flag('+') {
 			recurse = true
@@ -128,10 +368,10 @@ func (err msgError) Format(f fmt.State, c rune) {
 			io.WriteString(f, "\n")
 		}
 		if recurse {
-			if formatter, ok := err.wrapped.(fmt.Formatter); ok {
+			if formatter, ok := wrapped.(fmt.Formatter); ok {
 				formatter.Format(f, c)
 			} else {
-				io.WriteString(f, err.wrapped.Error())
+				io.WriteString(f, wrapped.Error())
 			}
 		}
 	}
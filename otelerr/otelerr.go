@@ -0,0 +1,107 @@
+// Package otelerr records errors on OpenTelemetry spans with the source reference of each
+// error in the chain, rather than only the text of err.Error().
+package otelerr
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// attribute keys
+const (
+	ExceptionMessage = attribute.Key("exception.message")
+	CodeFilepath     = attribute.Key("code.filepath") //file in its package, e.g. "github.com/my/pkg/file.go"
+	CodeLineno       = attribute.Key("code.lineno")
+	CodeFunction     = attribute.Key("code.function")
+	CodeNamespace    = attribute.Key("code.namespace") //package, e.g. "github.com/my/pkg"
+	ErrorCode        = attribute.Key("error.code")     //see errors.GetCode()
+	ErrorRetryable   = attribute.Key("error.retryable")
+	ErrorRetryAt     = attribute.Key("error.retry_at") //RFC3339 time, see errors.RetryableAt()
+	ErrorField       = attribute.Key("error.field")    //see errors.WrapField()
+)
+
+// RecordError() adds an "exception" event to span for each error in the chain, see errors.Frames(),
+// with the message and source reference, and sets the span status to Error,
+// except for 4xx codes on server spans, as the server did not fail.
+// It sets the span attributes error.code when err has a code, and error.retryable and error.retry_at.
+func RecordError(span trace.Span, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+	addEvents(span, errors.Frames(err))
+
+	attrs := []attribute.KeyValue{}
+	code, hasCode := errors.GetCode(err)
+	if hasCode {
+		attrs = append(attrs, ErrorCode.Int(code))
+	}
+	at, retryable := errors.RetryableAt(err)
+	attrs = append(attrs, ErrorRetryable.Bool(retryable))
+	if retryable {
+		attrs = append(attrs, ErrorRetryAt.String(at.Format(time.RFC3339Nano)))
+	}
+	span.SetAttributes(attrs...)
+
+	if hasCode && code >= 400 && code < 500 && isServer(span) {
+		return
+	}
+	span.SetStatus(codes.Error, err.Error())
+} //RecordError()
+
+func addEvents(span trace.Span, frames []errors.Frame) {
+	for _, frame := range frames {
+		attrs := []attribute.KeyValue{ExceptionMessage.String(frame.Message)}
+		if m := sourcePattern.FindStringSubmatch(frame.Source); m != nil {
+			line, _ := strconv.Atoi(m[2])
+			attrs = append(attrs,
+				CodeFilepath.String(m[1]),
+				CodeLineno.Int(line),
+				CodeNamespace.String(path.Dir(m[1])),
+			)
+		}
+		if frame.Function != "" {
+			attrs = append(attrs, CodeFunction.String(frame.Function))
+		}
+		if frame.Code != 0 {
+			attrs = append(attrs, ErrorCode.Int(frame.Code))
+		}
+		if frame.RetryAt != nil {
+			attrs = append(attrs, ErrorRetryAt.String(frame.RetryAt.Format(time.RFC3339Nano)))
+		}
+		if frame.Field != "" {
+			attrs = append(attrs, ErrorField.String(frame.Field))
+		}
+		span.AddEvent("exception", trace.WithAttributes(attrs...))
+		for _, joined := range frame.Joined {
+			addEvents(span, joined)
+		}
+	}
+}
+
+// source reference of a frame, like "github.com/my/pkg/file.go(12)"
+var sourcePattern = regexp.MustCompile(`^(.*)\((\d+)\)$`)
+
+func isServer(span trace.Span) bool {
+	s, ok := span.(interface{ SpanKind() trace.SpanKind })
+	return ok && s.SpanKind() == trace.SpanKindServer
+}
+
+// Traceparent() is an errors.Extractor of the W3C traceparent of the span in ctx,
+// to record it in errors created with a context:
+//
+//	errors.RegisterExtractor("traceparent", otelerr.Traceparent)
+func Traceparent(ctx context.Context) (string, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", false
+	}
+	return "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-" + sc.TraceFlags().String(), true
+}
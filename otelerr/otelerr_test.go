@@ -0,0 +1,113 @@
+package otelerr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func record(err error, opts ...trace.SpanStartOption) sdktrace.ReadOnlySpan {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := tp.Tracer("test").Start(context.Background(), "op", opts...)
+	RecordError(span, err)
+	span.End()
+	return recorder.Ended()[0]
+}
+
+func attrs(kvs []attribute.KeyValue) map[attribute.Key]any {
+	m := map[attribute.Key]any{}
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+func connect() error {
+	return errors.Retry(errors.Code(errors.Error("connection refused"), 503), time.Minute)
+}
+
+func TestRecordError(t *testing.T) {
+	err := errors.Wrap(connect(), "cannot load user")
+	span := record(err)
+
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, "cannot load user because connection refused", span.Status().Description)
+	spanAttrs := attrs(span.Attributes())
+	assert.Equal(t, int64(503), spanAttrs[ErrorCode])
+	assert.Equal(t, true, spanAttrs[ErrorRetryable])
+	at, _ := errors.RetryableAt(err)
+	assert.Equal(t, at.Format(time.RFC3339Nano), spanAttrs[ErrorRetryAt])
+
+	events := span.Events()
+	if assert.Len(t, events, 2) {
+		first := attrs(events[0].Attributes)
+		assert.Equal(t, "exception", events[0].Name)
+		assert.Equal(t, "cannot load user", first[ExceptionMessage])
+		assert.Equal(t, "github.com/go-msvc/errors/otelerr/otelerr_test.go", first[CodeFilepath])
+		assert.Equal(t, "github.com/go-msvc/errors/otelerr", first[CodeNamespace])
+		assert.Equal(t, "TestRecordError", first[CodeFunction])
+		assert.Greater(t, first[CodeLineno], int64(0))
+
+		second := attrs(events[1].Attributes)
+		assert.Equal(t, "connection refused", second[ExceptionMessage])
+		assert.Equal(t, "connect", second[CodeFunction])
+		assert.Equal(t, int64(503), second[ErrorCode])
+		assert.Equal(t, at.Format(time.RFC3339Nano), second[ErrorRetryAt])
+	}
+}
+
+func TestRecordValidation(t *testing.T) {
+	err := errors.Code(errors.Validate(context.Background(),
+		errors.Check{Field: "name", Value: func() error { return errors.Error("missing name") }},
+		errors.Check{Field: "email", Value: func() error { return errors.Error("missing email") }},
+	), 400)
+
+	//not a server error
+	span := record(err, trace.WithSpanKind(trace.SpanKindServer))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	assert.Equal(t, false, attrs(span.Attributes())[ErrorRetryable])
+	events := span.Events()
+	if assert.Len(t, events, 5) {
+		assert.Equal(t, int64(400), attrs(events[0].Attributes)[ErrorCode]) //joined frame
+		assert.Equal(t, "invalid name", attrs(events[1].Attributes)[ExceptionMessage])
+		assert.Equal(t, "name", attrs(events[1].Attributes)[ErrorField])
+		assert.Equal(t, "missing name", attrs(events[2].Attributes)[ExceptionMessage])
+		assert.Equal(t, "email", attrs(events[3].Attributes)[ErrorField])
+	}
+
+	//client spans fail
+	span = record(err, trace.WithSpanKind(trace.SpanKindClient))
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
+
+func TestNotRecording(t *testing.T) {
+	RecordError(trace.SpanFromContext(context.Background()), errors.Error("ignored"))
+	span := record(nil)
+	assert.Empty(t, span.Events())
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestTraceparent(t *testing.T) {
+	_, ok := Traceparent(context.Background())
+	assert.False(t, ok)
+
+	previous := errors.RegisterExtractor("traceparent", Traceparent)
+	t.Cleanup(func() {
+		errors.RegisterExtractor("traceparent", previous)
+	})
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	err := errors.ErrorCtx(ctx, "failed")
+	sc := span.SpanContext()
+	assert.Equal(t, []errors.ContextValue{{Name: "traceparent", Value: "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-01"}}, errors.ContextValues(err))
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Public() is like Error() but marks the message as safe to show to users,
+// so it is included in UserMessage() and the %u verb.
+// Messages of other errors are only written to logs with verbs like %+v,
+// as they may contain internal details like file paths or SQL.
+func Public(msg string) BaseError {
+	return &msgError{
+		baseError: baseError{
+			source: GetCaller(2),
+		},
+		msg:    msg,
+		public: true,
+	}
+}
+
+// Publicf() is like Public() but does message formatting
+func Publicf(format string, args ...interface{}) BaseError {
+	err := formatted(GetCaller(2), nil, format, args...)
+	err.public = true
+	return err
+}
+
+// WrapPublic() is like Wrap() but marks the message as safe to show to users
+func WrapPublic(err error, msg string) BaseError {
+	if err == nil {
+		return nil
+	}
+	return &msgError{
+		baseError: baseError{
+			wrapped: err,
+			source:  GetCaller(2),
+		},
+		msg:    msg,
+		public: true,
+	}
+}
+
+// WrapPublicAt() is like WrapPublic() but records source rather than where it is called,
+// for helpers that wrap errors for their callers, e.g. with GetCaller(3) for the caller of the helper
+func WrapPublicAt(source Caller, err error, msg string) BaseError {
+	if err == nil {
+		return nil
+	}
+	return &msgError{
+		baseError: baseError{
+			wrapped: err,
+			source:  source,
+		},
+		msg:    msg,
+		public: true,
+	}
+}
+
+// WrapPublicf() is like WrapPublic() but does message formatting
+func WrapPublicf(err error, format string, args ...interface{}) BaseError {
+	if err == nil {
+		return nil
+	}
+	perr := formatted(GetCaller(2), err, format, args...)
+	perr.public = true
+	return perr
+}
+
+// UserMessage() returns only the public messages in err and its wrapped errors,
+// linked with " because ", e.g. "invalid request because missing name",
+// or "" when none of the messages are public.
+// It is the same as formatting err with "%u", and "%-u" links the messages with "\n".
+func UserMessage(err error) string {
+	return userMessage(err, " because ")
+}
+
+// publicError is implemented by errors that can be marked public
+type publicError interface {
+	publicMessage() (msg string, ok bool)
+}
+
+func userMessage(err error, link string) string {
+	msgs := []string{}
+	for err != nil {
+		if pe, ok := err.(publicError); ok {
+			if msg, ok := pe.publicMessage(); ok {
+				msgs = append(msgs, msg)
+			}
+		}
+		switch e := err.(type) {
+		case interface{ next() error }:
+			err = e.next() //skip errors already in the message with %w
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			items := []string{}
+			for _, item := range e.Unwrap() {
+				if msg := userMessage(item, link); msg != "" {
+					items = append(items, msg)
+				}
+			}
+			if len(items) > 0 {
+				msgs = append(msgs, strings.Join(items, "; "))
+			}
+			err = nil
+		default:
+			err = nil
+		}
+	}
+	return strings.Join(msgs, link)
+} //userMessage()
+
+// userLink returns the link between messages written with the %u verb
+func userLink(f fmt.State) string {
+	if f.Flag('-') {
+		return "\n"
+	}
+	return " because "
+}
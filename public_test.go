@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublic(t *testing.T) {
+	_, origErr := os.Open("/some/file/that/does/not/exist")
+	err := Wrap(origErr, "cannot load config")
+	err = WrapPublicf(err, "cannot create user(%s)", "jan")
+	err = Code(err, 500)
+	err = WrapPublic(err, "request failed")
+
+	//all messages in the log
+	assert.Equal(t, "request failed because cannot create user(jan) because cannot load config because "+origErr.Error(), fmt.Sprintf("%+s", err))
+
+	//only public messages for the user
+	assert.Equal(t, "request failed because cannot create user(jan)", UserMessage(err))
+	assert.Equal(t, "request failed because cannot create user(jan)", fmt.Sprintf("%u", err))
+	assert.Equal(t, "request failed\ncannot create user(jan)", fmt.Sprintf("%-u", err))
+
+	//none public
+	assert.Equal(t, "", UserMessage(Wrap(origErr, "cannot load config")))
+	assert.Equal(t, "", UserMessage(origErr))
+
+	//field errors and joined errors
+	err = Public("missing street")
+	err = WrapField(err, "address")
+	err = join(GetCaller(1), err, Publicf("missing %s", "name"), Error("internal"))
+	assert.Equal(t, "invalid address because missing street; missing name", UserMessage(err))
+}
@@ -0,0 +1,364 @@
+// Package report sends errors to a central place, like a file or an HTTP collector,
+// so they can be seen across hosts and not only in the logs of each process.
+package report
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+// Reporter sends errors to a Transport
+type Reporter interface {
+	Report(err error)                //queue err to be sent, without blocking
+	Flush(ctx context.Context) error //send all queued errors
+	Close(ctx context.Context) error //flush and stop, errors reported after this are ignored
+}
+
+// Event is one reported error
+type Event struct {
+	Time        time.Time      `json:"time"` //when reported, or the last repeat
+	Host        string         `json:"host,omitempty"`
+	Fingerprint string         `json:"fingerprint"` //see errors.Fingerprint()
+	Message     string         `json:"msg"`
+	Code        int            `json:"code,omitempty"`
+	RetryAt     *time.Time     `json:"retry_at,omitempty"`
+	Fields      []string       `json:"fields,omitempty"` //json paths of invalid fields, see errors.FieldPath()
+	Frames      []errors.Frame `json:"frames"`
+	Count       int            `json:"count"` //more than 1 when repeats were deduplicated
+}
+
+// NewEvent() describes err, as it is sent by the Reporter
+func NewEvent(err error) Event {
+	e := Event{
+		Time:        time.Now(),
+		Fingerprint: errors.Fingerprint(err),
+		Message:     err.Error(),
+		Frames:      errors.Frames(err),
+		Count:       1,
+	}
+	e.Code, _ = errors.GetCode(err)
+	if at, ok := errors.RetryableAt(err); ok {
+		e.RetryAt = &at
+	}
+	e.Fields = fieldPaths(e.Frames, "")
+	return e
+}
+
+// fieldPaths returns the paths of fields in frames, which can be more than one when errors were joined
+func fieldPaths(frames []errors.Frame, path string) []string {
+	var paths []string
+	for _, frame := range frames {
+		if frame.Field != "" {
+			if path != "" {
+				path += "."
+			}
+			path += frame.Field
+		}
+		for _, joined := range frame.Joined {
+			paths = append(paths, fieldPaths(joined, path)...)
+		}
+		if len(frame.Joined) > 0 {
+			return paths
+		}
+	}
+	if path != "" {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// Transport sends a batch of events, see JSONLines() and HTTP().
+// A retryable error (see errors.RetryableAt()) is retried by the Reporter.
+type Transport interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// Option changes the defaults of New()
+type Option func(*options)
+
+type options struct {
+	queueSize     int
+	batchSize     int
+	flushInterval time.Duration
+	dedupeWindow  time.Duration
+	attempts      int
+	host          string
+	onError       func(error)
+}
+
+// WithQueueSize() sets how many errors can wait to be sent (default 1000),
+// more are dropped rather than blocking the caller of Report()
+func WithQueueSize(n int) Option {
+	return func(o *options) {
+		o.queueSize = n
+	}
+}
+
+// WithBatchSize() sets the most events sent at once (default 100)
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		o.batchSize = n
+	}
+}
+
+// WithFlushInterval() sets how long events wait for a batch to fill up (default 5s)
+func WithFlushInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.flushInterval = d
+	}
+}
+
+// WithDedupeWindow() sets how long errors with the same fingerprint are counted
+// rather than sent again (default 1 minute). The first one is sent as usual
+// and the repeats as one event with their count at the end of the window.
+// Use 0 to send all errors.
+func WithDedupeWindow(d time.Duration) Option {
+	return func(o *options) {
+		o.dedupeWindow = d
+	}
+}
+
+// WithAttempts() sets how many times a batch is sent when the transport returns a retryable error (default 3)
+func WithAttempts(n int) Option {
+	return func(o *options) {
+		o.attempts = n
+	}
+}
+
+// WithHost() sets the host name in events (default os.Hostname())
+func WithHost(host string) Option {
+	return func(o *options) {
+		o.host = host
+	}
+}
+
+// WithErrorHandler() sets the func called when events cannot be sent or were dropped (default log.Printf())
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// maximum wait before retrying a batch, to not hold up the queue for long
+const maxRetryWait = time.Minute
+
+// New() returns a Reporter that sends errors in batches from a goroutine,
+// so reporting does not slow down the caller. Call Close() before the process ends.
+func New(transport Transport, opts ...Option) Reporter {
+	host, _ := os.Hostname()
+	o := options{
+		queueSize:     1000,
+		batchSize:     100,
+		flushInterval: 5 * time.Second,
+		dedupeWindow:  time.Minute,
+		attempts:      3,
+		host:          host,
+		onError: func(err error) {
+			log.Printf("%+v", err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &reporter{
+		options:   o,
+		transport: transport,
+		queue:     make(chan Event, o.queueSize),
+		flushes:   make(chan chan error),
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+		windows:   map[string]*window{},
+	}
+	go r.run()
+	return r
+} //New()
+
+type reporter struct {
+	options
+	transport Transport
+	queue     chan Event
+	flushes   chan chan error
+	stop      chan struct{} //closed by Close()
+	stopped   chan struct{} //closed when run() returned
+	stopErr   error         //from the last send, set before stopped is closed
+	closed    atomic.Bool
+	dropped   atomic.Int64
+	ctx       context.Context //cancelled to abort sending when Close() times out
+	cancel    context.CancelFunc
+
+	//only used in run()
+	batch   []Event
+	windows map[string]*window //by fingerprint
+}
+
+// window of deduplication for one fingerprint
+type window struct {
+	start   time.Time
+	repeats *Event
+}
+
+func (r *reporter) Report(err error) {
+	if err == nil || r.closed.Load() {
+		return
+	}
+	e := NewEvent(err)
+	e.Host = r.host
+	select {
+	case r.queue <- e:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+func (r *reporter) Flush(ctx context.Context) error {
+	done := make(chan error, 1)
+	select {
+	case r.flushes <- done:
+	case <-r.stopped:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "cannot flush errors")
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "cannot flush errors")
+	}
+}
+
+func (r *reporter) Close(ctx context.Context) error {
+	if !r.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	close(r.stop)
+	select {
+	case <-r.stopped:
+		return r.stopErr
+	case <-ctx.Done():
+		r.cancel() //abort sending
+		return errors.Wrap(ctx.Err(), "cannot flush errors")
+	}
+}
+
+func (r *reporter) run() {
+	defer close(r.stopped)
+	defer r.cancel()
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case e := <-r.queue:
+			r.add(e)
+			if len(r.batch) >= r.batchSize {
+				r.send()
+			}
+		case <-ticker.C:
+			r.expire(false)
+			r.send()
+		case done := <-r.flushes:
+			r.drain()
+			done <- r.send()
+		case <-r.stop:
+			r.drain()
+			r.stopErr = r.send()
+			return
+		}
+	}
+} //reporter.run()
+
+// drain adds all queued events to the batch, with all repeats counted so far
+func (r *reporter) drain() {
+	for {
+		select {
+		case e := <-r.queue:
+			r.add(e)
+		default:
+			r.expire(true)
+			return
+		}
+	}
+}
+
+// add e to the batch, or count it as a repeat
+func (r *reporter) add(e Event) {
+	if r.dedupeWindow <= 0 {
+		r.batch = append(r.batch, e)
+		return
+	}
+	w, ok := r.windows[e.Fingerprint]
+	if ok && e.Time.Sub(w.start) < r.dedupeWindow {
+		if w.repeats != nil {
+			e.Count += w.repeats.Count
+		}
+		w.repeats = &e //the last repeat, with the count of all repeats
+		return
+	}
+	if ok && w.repeats != nil {
+		r.batch = append(r.batch, *w.repeats)
+	}
+	r.windows[e.Fingerprint] = &window{start: e.Time}
+	r.batch = append(r.batch, e)
+}
+
+// expire adds the repeats of windows that ended to the batch,
+// or of all windows when flushing, then windows stay open to keep counting
+func (r *reporter) expire(flush bool) {
+	now := time.Now()
+	for fingerprint, w := range r.windows {
+		ended := now.Sub(w.start) >= r.dedupeWindow
+		if (ended || flush) && w.repeats != nil {
+			r.batch = append(r.batch, *w.repeats)
+			w.repeats = nil
+		}
+		if ended {
+			delete(r.windows, fingerprint)
+		}
+	}
+}
+
+// send the batch in parts of batchSize, retrying when the transport returns a retryable error
+func (r *reporter) send() error {
+	if n := r.dropped.Swap(0); n > 0 {
+		r.onError(errors.Errorf("report queue full, dropped %d errors", n))
+	}
+	var failed error
+	for len(r.batch) > 0 {
+		n := min(len(r.batch), r.batchSize)
+		if err := r.sendBatch(r.batch[:n]); err != nil {
+			r.onError(err)
+			failed = err
+		}
+		r.batch = r.batch[n:]
+	}
+	r.batch = nil
+	return failed
+}
+
+func (r *reporter) sendBatch(events []Event) error {
+	for attempt := 1; ; attempt++ {
+		err := r.transport.Send(r.ctx, events)
+		if err == nil {
+			return nil
+		}
+		at, ok := errors.RetryableAt(err)
+		if !ok || attempt >= r.attempts {
+			return errors.Wrapf(err, "cannot send %d errors", len(events))
+		}
+		timer := time.NewTimer(min(time.Until(at), maxRetryWait))
+		select {
+		case <-timer.C:
+		case <-r.ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(err, "cannot send %d errors", len(events))
+		}
+	}
+}
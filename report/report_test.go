@@ -0,0 +1,111 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func lines(t *testing.T, buf *bytes.Buffer) []Event {
+	events := []Event{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		e := Event{}
+		if assert.NoError(t, json.Unmarshal([]byte(line), &e), line) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func failed(name string) error {
+	return errors.Codef(404, "user %s not found", name)
+}
+
+func TestDedupe(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := New(JSONLines(buf), WithHost("test-host"))
+	r.Report(failed("a"))
+	r.Report(failed("b"))
+	r.Report(errors.Error("other"))
+	r.Report(failed("c"))
+	r.Report(nil)
+	assert.NoError(t, r.Close(context.Background()))
+	r.Report(failed("d")) //ignored after Close()
+
+	events := lines(t, buf)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "user a not found", events[0].Message)
+		assert.Equal(t, 404, events[0].Code)
+		assert.Equal(t, 1, events[0].Count)
+		assert.Equal(t, "test-host", events[0].Host)
+		assert.Equal(t, errors.Fingerprint(failed("x")), events[0].Fingerprint)
+		assert.Len(t, events[0].Frames, 1)
+
+		assert.Equal(t, "other", events[1].Message)
+		assert.Equal(t, 1, events[1].Count)
+
+		assert.Equal(t, "user c not found", events[2].Message) //last repeat
+		assert.Equal(t, 2, events[2].Count)
+	}
+}
+
+func TestNoDedupe(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := New(JSONLines(buf), WithDedupeWindow(0))
+	r.Report(failed("a"))
+	r.Report(failed("b"))
+	assert.NoError(t, r.Flush(context.Background()))
+	assert.Len(t, lines(t, buf), 2)
+	assert.NoError(t, r.Close(context.Background()))
+	assert.NoError(t, r.Close(context.Background()))
+}
+
+func TestEventFields(t *testing.T) {
+	err := errors.WrapField(errors.Validate(context.Background(),
+		errors.Check{Field: "street", Value: func() error { return errors.Error("missing street") }},
+		errors.Check{Field: "zip", Value: func() error { return errors.WrapField(errors.Error("too long"), "code") }},
+	), "address")
+	assert.Equal(t, []string{"address.street", "address.zip.code"}, NewEvent(err).Fields)
+	assert.Nil(t, NewEvent(errors.Error("no fields")).Fields)
+}
+
+// blocking transport waits for release before each batch is sent
+type blocking struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t blocking) Send(ctx context.Context, events []Event) error {
+	t.started <- struct{}{}
+	<-t.release
+	return nil
+}
+
+func TestQueueFull(t *testing.T) {
+	transport := blocking{started: make(chan struct{}), release: make(chan struct{})}
+	mutex := sync.Mutex{}
+	reported := []string{}
+	r := New(transport, WithQueueSize(1), WithBatchSize(1), WithDedupeWindow(0), WithErrorHandler(func(err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		reported = append(reported, err.Error())
+	}))
+	r.Report(failed("a"))
+	<-transport.started
+	for i := 0; i < 5; i++ {
+		r.Report(failed("b")) //1 queued and 4 dropped
+	}
+	close(transport.release)
+	go func() {
+		for range transport.started {
+		}
+	}()
+	assert.NoError(t, r.Close(context.Background()))
+	assert.Equal(t, []string{"report queue full, dropped 4 errors"}, reported)
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+// JSONLines() returns a Transport that writes each event as a line of JSON to w, e.g. a file:
+//
+//	f, err := os.OpenFile("errors.jsonl", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+//	r := report.New(report.JSONLines(f))
+func JSONLines(w io.Writer) Transport {
+	return &jsonLines{w: w}
+}
+
+type jsonLines struct {
+	sync.Mutex
+	w io.Writer
+}
+
+func (t *jsonLines) Send(ctx context.Context, events []Event) error {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return errors.Wrap(err, "cannot encode event")
+		}
+	}
+	t.Lock()
+	defer t.Unlock()
+	if _, err := t.w.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "cannot write events")
+	}
+	return nil
+}
+
+// default wait before sending to the collector again, unless it responded with Retry-After
+const retryDelay = time.Second
+
+// HTTP() returns a Transport that posts each batch as a JSON array of events to url.
+// Network errors and responses 429 (Too Many Requests) and 5xx are retryable,
+// after the Retry-After response header when specified.
+// When client is nil, http.DefaultClient is used.
+func HTTP(url string, client *http.Client) Transport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return httpTransport{url: url, client: client}
+}
+
+type httpTransport struct {
+	url    string
+	client *http.Client
+}
+
+func (t httpTransport) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode events")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "cannot create request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpRes, err := t.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return errors.Wrapf(err, "cannot post to %s", t.url)
+		}
+		return errors.Retry(errors.Wrapf(err, "cannot post to %s", t.url), retryDelay)
+	}
+	defer httpRes.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(httpRes.Body, 4096))
+	if httpRes.StatusCode >= 200 && httpRes.StatusCode < 300 {
+		return nil
+	}
+	err = errors.Codef(httpRes.StatusCode, "collector %s responded %s", t.url, httpRes.Status)
+	if httpRes.StatusCode == http.StatusTooManyRequests || httpRes.StatusCode >= 500 {
+		return errors.Retry(err, retryAfter(httpRes.Header.Get("Retry-After")))
+	}
+	return err
+} //httpTransport.Send()
+
+// retryAfter returns the wait in a Retry-After header, in seconds or an HTTP date
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return retryDelay
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return retryDelay
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-msvc/errors/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// collector responds with the statuses in order, then 200
+type collector struct {
+	sync.Mutex
+	statuses []int
+	requests int
+	events   []Event
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, httpReq *http.Request) {
+	c.Lock()
+	defer c.Unlock()
+	c.requests++
+	if len(c.statuses) > 0 {
+		status := c.statuses[0]
+		c.statuses = c.statuses[1:]
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(status)
+		return
+	}
+	events := []Event{}
+	json.NewDecoder(httpReq.Body).Decode(&events)
+	c.events = append(c.events, events...)
+}
+
+func TestHTTPRetry(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	r := New(HTTP(server.URL, server.Client()), WithDedupeWindow(0))
+	r.Report(failed("a"))
+	r.Report(errors.Retry(failed("b"), time.Minute))
+	assert.NoError(t, r.Close(context.Background()))
+	assert.Equal(t, 3, c.requests)
+	if assert.Len(t, c.events, 2) {
+		assert.Equal(t, "user a not found", c.events[0].Message)
+		assert.Nil(t, c.events[0].RetryAt)
+		assert.NotNil(t, c.events[1].RetryAt)
+	}
+}
+
+func TestHTTPNotRetried(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusBadRequest, http.StatusServiceUnavailable}}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	reported := []error{}
+	r := New(HTTP(server.URL, server.Client()), WithAttempts(1), WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+	r.Report(failed("a"))
+	err := r.Flush(context.Background())
+	assert.EqualError(t, err, "cannot send 1 errors because collector "+server.URL+" responded 400 Bad Request")
+	code, _ := errors.GetCode(err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	r.Report(failed("b"))
+	r.Report(errors.Error("other"))
+	err = r.Close(context.Background())
+	assert.True(t, errors.IsRetryable(err), "503 is retryable but not attempted again")
+	assert.Len(t, reported, 2)
+	assert.Equal(t, 2, c.requests)
+	assert.Empty(t, c.events)
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, retryDelay, retryAfter(""))
+	assert.Equal(t, 2*time.Second, retryAfter("2"))
+	assert.Equal(t, retryDelay, retryAfter("soon"))
+	d := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, d > 59*time.Minute && d <= time.Hour, d)
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// RetryBudget limits retries to a dependency that is failing, shared by all goroutines calling it,
+// so that retries do not add to its load, like retry throttling in gRPC:
+// each retryable failure takes a token and each success adds tokenRatio of a token, up to maxTokens,
+// and retries are only allowed while more than half of maxTokens are left.
+// It is safe for concurrent use.
+type RetryBudget struct {
+	maxTokens  int64 //in thousandths of a token
+	tokenRatio int64 //in thousandths of a token
+	tokens     atomic.Int64
+}
+
+// NewRetryBudget() returns a budget that starts with maxTokens.
+// Each retryable failure takes one token and each success adds tokenRatio of a token,
+// and retries are allowed while more than half of maxTokens are left.
+// E.g. NewRetryBudget(10, 0.1) allows 4 retries in a row when full,
+// and then about one more for every 10 successful calls.
+func NewRetryBudget(maxTokens, tokenRatio float64) *RetryBudget {
+	b := &RetryBudget{
+		maxTokens:  int64(maxTokens * 1000),
+		tokenRatio: int64(tokenRatio * 1000),
+	}
+	b.tokens.Store(b.maxTokens)
+	return b
+}
+
+// Success() is called after each successful call, to add to the budget
+func (b *RetryBudget) Success() {
+	for {
+		tokens := b.tokens.Load()
+		if tokens >= b.maxTokens || b.tokens.CompareAndSwap(tokens, min(tokens+b.tokenRatio, b.maxTokens)) {
+			return
+		}
+	}
+}
+
+// Check() is called after a call failed and before retrying it.
+// It returns err when err is not retryable (see ShouldRetry()) or the budget allows the retry,
+// else err wrapped as Permanent() with code 503 (Service Unavailable) to say the budget is spent.
+func (b *RetryBudget) Check(err error) error {
+	if _, ok := ShouldRetry(err); !ok {
+		return err
+	}
+	var tokens int64
+	for {
+		tokens = b.tokens.Load()
+		if b.tokens.CompareAndSwap(tokens, max(tokens-1000, 0)) {
+			break
+		}
+	}
+	if tokens-1000 > b.maxTokens/2 {
+		return err
+	}
+	return Permanent(Code(Wrap(err, "retry budget spent"), http.StatusServiceUnavailable))
+} //RetryBudget.Check()
+
+// Tokens() returns the tokens left
+func (b *RetryBudget) Tokens() float64 {
+	return float64(b.tokens.Load()) / 1000
+}
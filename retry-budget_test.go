@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBudget(t *testing.T) {
+	b := NewRetryBudget(10, 0.1)
+	assert.Equal(t, 10.0, b.Tokens())
+	unavailable := Codef(503, "unavailable")
+
+	//4 retries take the budget to 6 tokens
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, unavailable, b.Check(unavailable))
+	}
+	//not retryable, so not counted
+	notFound := Codef(404, "not found")
+	assert.Equal(t, notFound, b.Check(notFound))
+	assert.Equal(t, 6.0, b.Tokens())
+
+	//5 tokens is not more than half
+	err := b.Check(Wrap(unavailable, "cannot call"))
+	assert.Equal(t, 5.0, b.Tokens())
+	assert.Equal(t, "retry budget spent because cannot call because unavailable", err.Error())
+	assert.True(t, IsPermanent(err))
+	_, ok := ShouldRetry(err)
+	assert.False(t, ok)
+	code, _ := GetCode(err)
+	assert.Equal(t, 503, code)
+	assert.Equal(t, "Check", err.(BaseError).Source().Function())
+	assert.True(t, Is(err, unavailable))
+
+	//successes refill it
+	for i := 0; i < 11; i++ {
+		b.Success()
+	}
+	assert.InDelta(t, 6.1, b.Tokens(), 0.001)
+	assert.Equal(t, unavailable, b.Check(unavailable))
+
+	for i := 0; i < 100; i++ {
+		b.Success()
+	}
+	assert.Equal(t, 10.0, b.Tokens())
+	for i := 0; i < 20; i++ {
+		b.Check(Retry(Error("busy"), time.Second))
+	}
+	assert.Equal(t, 0.0, b.Tokens())
+}
+
+func TestRetryBudgetConcurrent(t *testing.T) {
+	b := NewRetryBudget(1000, 1)
+	unavailable := Codef(503, "unavailable")
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 40; j++ {
+				b.Check(unavailable)
+				b.Success()
+				b.Check(unavailable)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 600.0, b.Tokens())
+}
@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ShouldRetry() returns when err can be retried, from Retry() or Retryf() in err, see RetryableAt(),
+// or ok=false when it is Permanent() or Ambiguous() and not wrapped by Idempotent(),
+// else when a registered Classifier recognises err as a transient failure, see RegisterClassifier().
+// It returns ok=false when err should not be retried.
+func ShouldRetry(err error) (at time.Time, ok bool) {
+	if err == nil {
+		return at, false
+	}
+	if at, retryable, decided := retryDecision(err); decided {
+		return at, retryable
+	}
+	for _, c := range *classifiers.Load() {
+		if wait, ok := c.fn(err); ok {
+			if wait <= 0 {
+				wait = time.Duration(retryDelay.Load())
+			}
+			return time.Now().Add(wait), true
+		}
+	}
+	return at, false
+}
+
+// Classifier returns ok=true when err is a transient failure that can be retried after wait,
+// or after the default delay when wait is 0, see SetRetryDelay()
+type Classifier func(err error) (wait time.Duration, ok bool)
+
+type namedClassifier struct {
+	name string
+	fn   Classifier
+}
+
+var (
+	classifiersMutex sync.Mutex //for changes, as classifiers are read without locking
+	classifiers      atomic.Pointer[[]namedClassifier]
+	retryDelay       atomic.Int64
+)
+
+func init() {
+	classifiers.Store(&[]namedClassifier{
+		{name: "network", fn: ClassifyNetwork},
+		{name: "code", fn: ClassifyCode},
+	})
+	retryDelay.Store(int64(time.Second))
+}
+
+// RegisterClassifier() adds a Classifier used by ShouldRetry(), e.g. for the errors of a database driver,
+// or replaces the classifier with the same name, or removes it when fn is nil.
+// The defaults are "network" (ClassifyNetwork) and "code" (ClassifyCode).
+func RegisterClassifier(name string, fn Classifier) {
+	classifiersMutex.Lock()
+	defer classifiersMutex.Unlock()
+	list := slices.DeleteFunc(slices.Clone(*classifiers.Load()), func(c namedClassifier) bool {
+		return c.name == name
+	})
+	if fn != nil {
+		list = append(list, namedClassifier{name: name, fn: fn})
+	}
+	classifiers.Store(&list)
+}
+
+// SetRetryDelay() sets the wait before retrying errors recognised by a Classifier that did not specify the wait (default 1s)
+func SetRetryDelay(d time.Duration) {
+	retryDelay.Store(int64(d))
+}
+
+// ClassifyNetwork() recognises network timeouts, ECONNRESET and ECONNREFUSED,
+// and connections closed in the middle of a response (io.ErrUnexpectedEOF).
+// The deadline or cancellation of the caller's context is not retryable.
+func ClassifyNetwork(err error) (time.Duration, bool) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return 0, false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return 0, true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return 0, true
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		var opErr *net.OpError
+		var urlErr *url.Error
+		if errors.As(err, &opErr) || errors.As(err, &urlErr) {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// ClassifyCode() recognises the HTTP codes 429 (Too Many Requests), 502 (Bad Gateway),
+// 503 (Service Unavailable) and 504 (Gateway Timeout), see GetCode()
+func ClassifyCode(err error) (time.Duration, bool) {
+	code, ok := GetCode(err)
+	if !ok {
+		return 0, false
+	}
+	switch code {
+	case 429, 502, 503, 504:
+		return 0, true
+	}
+	return 0, false
+}
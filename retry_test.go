@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldRetry(t *testing.T) {
+	start := time.Now()
+	assertRetry := func(err error, wait time.Duration) {
+		t.Helper()
+		at, ok := ShouldRetry(err)
+		if assert.True(t, ok, "%+v", err) {
+			assert.WithinDuration(t, start.Add(wait), at, 100*time.Millisecond, "%+v", err)
+		}
+	}
+	assertNoRetry := func(err error) {
+		t.Helper()
+		_, ok := ShouldRetry(err)
+		assert.False(t, ok, "%+v", err)
+	}
+
+	assertRetry(Retry(Error("busy"), time.Minute), time.Minute)
+	assertRetry(Wrap(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, "cannot read"), time.Second)
+	assertRetry(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, time.Second)
+	assertRetry(Wrap(&net.DNSError{Err: "timeout", IsTimeout: true}, "cannot resolve"), time.Second)
+	assertRetry(Wrap(&url.Error{Op: "Get", URL: "http://x", Err: io.ErrUnexpectedEOF}, "cannot get"), time.Second)
+	assertRetry(Codef(503, "unavailable"), time.Second)
+	assertRetry(Code(Error("slow down"), 429), time.Second)
+
+	assertNoRetry(nil)
+	assertNoRetry(Error("failed"))
+	assertNoRetry(io.ErrUnexpectedEOF) //not from a connection
+	assertNoRetry(Wrap(context.DeadlineExceeded, "cannot call"))
+	assertNoRetry(Codef(500, "internal"))
+	assertNoRetry(Codef(400, "invalid"))
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	errLocked := Error("database is locked")
+	RegisterClassifier("db", func(err error) (time.Duration, bool) {
+		return 5 * time.Second, Is(err, errLocked)
+	})
+	RegisterClassifier("code", nil)
+	SetRetryDelay(time.Minute)
+	defer func() {
+		RegisterClassifier("db", nil)
+		RegisterClassifier("code", ClassifyCode)
+		SetRetryDelay(time.Second)
+	}()
+
+	at, ok := ShouldRetry(Wrap(errLocked, "cannot insert"))
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(5*time.Second), at, 100*time.Millisecond)
+
+	_, ok = ShouldRetry(Codef(503, "unavailable"))
+	assert.False(t, ok, "code classifier removed")
+
+	at, ok = ShouldRetry(&net.DNSError{Err: "timeout", IsTimeout: true})
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), at, 100*time.Millisecond)
+}
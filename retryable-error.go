@@ -1,8 +1,6 @@
 package errors
 
 import (
-	"errors"
-	"fmt"
 	"time"
 )
 
@@ -20,28 +18,97 @@ func Retry(err error, wait time.Duration) RetryableError {
 }
 
 func Retryf(wait time.Duration, format string, args ...interface{}) RetryableError {
+	source := GetCaller(2)
 	return retryableError{
 		baseError: baseError{
-			wrapped: fmt.Errorf(format, args...),
-			source:  GetCaller(2),
+			wrapped: formatted(source, nil, format, args...),
+			source:  source,
 		},
 		at: time.Now().Add(wait),
 	}
 }
 
+// IsRetryable() returns true when the outermost decision in err is Retry() or Retryf(), not Permanent(),
+// and err is not Ambiguous(), unless that is wrapped by Idempotent()
 func IsRetryable(err error) bool {
-	var re RetryableError
-	return errors.As(err, &re)
+	_, retryable, _ := retryDecision(err)
+	return retryable
 }
 
+// RetryableAt() returns when err can be retried, when the outermost decision in err is Retry() or Retryf(), not Permanent(),
+// and err is not Ambiguous(), unless that is wrapped by Idempotent()
 func RetryableAt(err error) (at time.Time, ok bool) {
-	var re RetryableError
-	if errors.As(err, &re) {
-		return re.CanRetryAt(), true
+	at, retryable, _ := retryDecision(err)
+	return at, retryable
+}
+
+// Permanent() wraps err to say it must not be retried, even when a wrapped error is retryable,
+// e.g. when a payment was already submitted and a lower layer returned Retry(err, 5*time.Second).
+// The outermost decision wins in IsRetryable(), RetryableAt() and ShouldRetry(),
+// so it can still be wrapped with Retry() by a layer that knows better.
+// Joined errors are permanent when any of them is, even when others are retryable.
+func Permanent(err error) BaseError {
+	if err == nil {
+		return nil
+	}
+	return permanentError{
+		baseError: baseError{
+			wrapped: err,
+			source:  GetCaller(2),
+		},
+	}
+}
+
+// IsPermanent() returns true when the outermost decision in err is Permanent()
+func IsPermanent(err error) bool {
+	_, retryable, decided := outermostDecision(err)
+	return decided && !retryable
+}
+
+// retryDecision returns the outermost decision in err, see outermostDecision(),
+// which is not to retry when err has an ambiguous error that is not wrapped by Idempotent()
+func retryDecision(err error) (at time.Time, retryable bool, decided bool) {
+	at, retryable, decided = outermostDecision(err)
+	if (retryable || !decided) && ambiguousNotIdempotent(err, false) {
+		return time.Time{}, false, true
 	}
-	return at, false
+	return at, retryable, decided
 }
 
+// outermostDecision returns the outermost decision in err, from Retry() or Permanent(),
+// in the same order that errors.As() searches the wrapped errors,
+// except that joined errors are not retryable when any of them is permanent
+func outermostDecision(err error) (at time.Time, retryable bool, decided bool) {
+	for err != nil {
+		if p, ok := err.(interface{ Permanent() bool }); ok && p.Permanent() {
+			return at, false, true
+		}
+		if re, ok := err.(RetryableError); ok {
+			return re.CanRetryAt(), true, true
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			//Permanent() in any of the joined errors wins over Retry() in the others
+			for _, item := range e.Unwrap() {
+				if itemAt, itemRetryable, itemDecided := outermostDecision(item); itemDecided {
+					if !itemRetryable {
+						return time.Time{}, false, true
+					}
+					if !decided {
+						at, retryable, decided = itemAt, true, true
+					}
+				}
+			}
+			return at, retryable, decided
+		default:
+			return at, false, false
+		}
+	}
+	return at, false, false
+} //outermostDecision()
+
 type RetryableError interface {
 	BaseError
 	Retryable
@@ -65,3 +132,17 @@ func (err retryableError) CanRetryAt() time.Time {
 func (err retryableError) String() string {
 	return err.at.String()
 }
+
+type permanentError struct {
+	baseError
+}
+
+// Permanent() is true for all errors made with Permanent(),
+// implement this in your own error types to make them permanent too
+func (err permanentError) Permanent() bool {
+	return true
+}
+
+func (err permanentError) String() string {
+	return "permanent"
+}
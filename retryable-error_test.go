@@ -70,3 +70,49 @@ func TestRetryableError(t *testing.T) {
 	err = Unwrap(err)
 	assert.False(t, IsRetryable(err))
 }
+
+func TestPermanent(t *testing.T) {
+	inner := Retry(Error("connection reset"), 5*time.Second)
+	err := Wrap(Permanent(Wrap(inner, "cannot submit payment")), "cannot checkout")
+	assert.Equal(t, "cannot checkout because cannot submit payment because connection reset", err.Error())
+	assert.False(t, IsRetryable(err))
+	_, ok := RetryableAt(err)
+	assert.False(t, ok)
+	_, ok = ShouldRetry(err)
+	assert.False(t, ok)
+	assert.True(t, IsPermanent(err))
+	assert.True(t, Is(err, inner), "still wrapped")
+	assert.Nil(t, Permanent(nil))
+
+	//not even classified as transient
+	_, ok = ShouldRetry(Permanent(Codef(503, "unavailable")))
+	assert.False(t, ok)
+
+	//outermost decision wins
+	err = Retry(err, time.Minute)
+	at, ok := RetryableAt(err)
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), at, time.Second)
+	assert.False(t, IsPermanent(err))
+	assert.False(t, IsPermanent(Error("undecided")))
+
+	//permanent anywhere in joined errors wins
+	joined := Join(Error("one"), Permanent(Error("two")), inner)
+	assert.False(t, IsRetryable(joined))
+	assert.True(t, IsPermanent(joined))
+	joined = Join(Retry(Error("one"), time.Second), Permanent(Error("two")))
+	assert.False(t, IsRetryable(joined))
+	_, ok = ShouldRetry(joined)
+	assert.False(t, ok)
+	assert.True(t, IsPermanent(joined))
+
+	//else the first retryable
+	joined = Join(Error("one"), inner, Retry(Error("two"), time.Hour))
+	at, ok = RetryableAt(joined)
+	assert.True(t, ok)
+	assert.WithinDuration(t, inner.CanRetryAt(), at, 0)
+
+	frames := Frames(Wrap(Permanent(Codef(409, "already paid")), "cannot pay"))
+	assert.True(t, frames[1].Permanent)
+	assert.Equal(t, 409, frames[1].Code)
+}
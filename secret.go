@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Secret() wraps a value that is formatted into an error message, like a token, email or card number,
+// so that it is redacted in Error(), %s, %v and JSON output:
+//
+//	errors.Errorf("invalid token %s", errors.Secret(token))
+//
+// Add the '#' flag when formatting the error, e.g. "%#+v", to show the real value when debugging locally.
+func Secret(v any) SecretValue {
+	return SecretValue{value: v}
+}
+
+// SecretValue is the value returned by Secret()
+type SecretValue struct {
+	value any
+}
+
+// Value() returns the real value
+func (s SecretValue) Value() any {
+	return s.value
+}
+
+func (s SecretValue) String() string {
+	return redact(s.value)
+}
+
+func (s SecretValue) Format(f fmt.State, c rune) {
+	io.WriteString(f, redact(s.value))
+}
+
+func (s SecretValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redact(s.value))
+}
+
+// Redactor returns the text written in place of a secret value
+type Redactor func(v any) string
+
+var redactor atomic.Pointer[Redactor]
+
+// SetRedactor() changes how secret values are written, e.g. SetRedactor(errors.RedactHMAC(key)).
+// The default is RedactStars, which is restored with SetRedactor(nil).
+func SetRedactor(r Redactor) {
+	if r == nil {
+		redactor.Store(nil)
+		return
+	}
+	redactor.Store(&r)
+}
+
+// RedactStars writes "***" for all secrets
+func RedactStars(v any) string {
+	return "***"
+}
+
+// RedactHMAC() returns a Redactor that writes a short HMAC-SHA256 of the value with key,
+// so the same secret can be matched in different errors without showing it.
+// The key must be a secret of at least 32 random bytes, e.g. loaded from your secret store,
+// because without it values like emails or card numbers can be found by hashing guesses.
+// Use the same key in all services whose logs are matched. Without a key, secrets are written as "***".
+func RedactHMAC(key []byte) Redactor {
+	if len(key) == 0 {
+		return RedactStars
+	}
+	key = bytes.Clone(key)
+	return func(v any) string {
+		mac := hmac.New(sha256.New, key)
+		io.WriteString(mac, fmt.Sprint(v))
+		return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:6])
+	}
+}
+
+func redact(v any) string {
+	if r := redactor.Load(); r != nil {
+		return (*r)(v)
+	}
+	return RedactStars(v)
+}
+
+// unsafeArgs returns args with secrets replaced by their real values,
+// or nil when there are no secrets in args
+func unsafeArgs(args []any) []any {
+	var unsafe []any
+	for i, arg := range args {
+		if s, ok := arg.(SecretValue); ok {
+			if unsafe == nil {
+				unsafe = make([]any, len(args))
+				copy(unsafe, args)
+			}
+			unsafe[i] = s.value
+		}
+	}
+	return unsafe
+}
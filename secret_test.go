@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecret(t *testing.T) {
+	const token = "abc123"
+	err := Errorf("invalid token %s", Secret(token))
+	err = Wrapf(err, "user(%s) not authenticated", Secret("jan@example.com"))
+
+	//redacted
+	assert.Equal(t, "user(***) not authenticated because invalid token ***", err.Error())
+	assert.Equal(t, "user(***) not authenticated", fmt.Sprintf("%s", err))
+	assert.Equal(t, "user(***) not authenticated because invalid token ***", fmt.Sprintf("%+s", err))
+	assert.False(t, strings.Contains(fmt.Sprintf("%+v", err), token))
+	data, _ := json.Marshal(map[string]any{"token": Secret(token)})
+	assert.Equal(t, `{"token":"***"}`, string(data))
+
+	//real values with '#'
+	assert.Equal(t, "user(jan@example.com) not authenticated because invalid token abc123", fmt.Sprintf("%#+s", err))
+	assert.Equal(t, "- user(jan@example.com) not authenticated\n  invalid token abc123", fmt.Sprintf("%#-s", join(GetCaller(1), err)))
+
+	//hashed with a key
+	redactHMAC := RedactHMAC([]byte("0123456789abcdef0123456789abcdef"))
+	SetRedactor(redactHMAC)
+	defer SetRedactor(nil)
+	assert.Equal(t, "invalid token "+redactHMAC(token), Errorf("invalid token %s", Secret(token)).Error())
+	assert.True(t, strings.HasPrefix(redactHMAC(token), "hmac:"))
+	assert.Len(t, redactHMAC(token), len("hmac:")+12)
+	assert.Equal(t, redactHMAC(token), RedactHMAC([]byte("0123456789abcdef0123456789abcdef"))(token), "same key")
+	assert.NotEqual(t, redactHMAC(token), redactHMAC("other"))
+	assert.NotEqual(t, redactHMAC(token), RedactHMAC([]byte("another key of thirty-two bytes!"))(token), "other key")
+	assert.Equal(t, "***", RedactHMAC(nil)(token), "not hashed without a key")
+}
@@ -1,5 +1,130 @@
 package errors
 
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
 type Validator interface {
 	Validate() error
 }
+
+// ValidatorContext is a Validator for checks that need I/O, e.g. to see if a username
+// is already taken, so they can honour the deadline and cancellation of ctx
+type ValidatorContext interface {
+	Validate(ctx context.Context) error
+}
+
+// Check is one validation done by Validate() or ValidateParallel()
+type Check struct {
+	Field string //json path of the field, used to wrap the error, e.g. "address"
+	Value any    //Validator, ValidatorContext, func() error or func(context.Context) error, skipped when a nil pointer or func
+}
+
+// Validate() runs the checks one after the other and returns nil when all passed,
+// else each failure wrapped with its field, e.g. "invalid address because missing street",
+// and joined when more than one failed.
+// Checks are not started after ctx is done, and then ctx.Err() is included in the result.
+func Validate(ctx context.Context, checks ...Check) error {
+	return validate(ctx, GetCaller(2), 1, checks)
+}
+
+// ValidateParallel() is like Validate() but runs up to limit checks at the same time,
+// or all of them when limit <= 0. Failures are still reported in the order of the checks.
+func ValidateParallel(ctx context.Context, limit int, checks ...Check) error {
+	return validate(ctx, GetCaller(2), limit, checks)
+}
+
+func validate(ctx context.Context, source Caller, limit int, checks []Check) error {
+	if limit <= 0 || limit > len(checks) {
+		limit = len(checks)
+	}
+	errs := make([]error, len(checks)+1) //last one is for ctx.Err()
+	sem := make(chan struct{}, limit)
+	wg := sync.WaitGroup{}
+	for i, check := range checks {
+		if ctx.Err() != nil {
+			break
+		}
+		if limit == 1 {
+			errs[i] = runCheck(ctx, source, check)
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = runCheck(ctx, source, check)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		errs[len(checks)] = &msgError{
+			baseError: baseError{
+				wrapped: err,
+				source:  source,
+			},
+			msg: "validation not completed",
+		}
+	}
+
+	list := []error{}
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+	if len(list) == 1 {
+		return list[0]
+	}
+	return join(source, list...)
+} //validate()
+
+func runCheck(ctx context.Context, source Caller, check Check) error {
+	if check.Value == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(check.Value); (v.Kind() == reflect.Pointer || v.Kind() == reflect.Func) && v.IsNil() {
+		return nil //optional field or check not specified
+	}
+
+	var err error
+	switch v := check.Value.(type) {
+	case ValidatorContext:
+		err = v.Validate(ctx)
+	case Validator:
+		err = v.Validate()
+	case func(context.Context) error:
+		err = v(ctx)
+	case func() error:
+		err = v()
+	default:
+		err = &msgError{
+			baseError: baseError{
+				source: source,
+			},
+			msg: reflect.TypeOf(check.Value).String() + " is not a validator",
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	if check.Field == "" {
+		return err
+	}
+	return fieldError{
+		msgError: msgError{
+			baseError: baseError{
+				wrapped: err,
+				source:  source,
+			},
+			msg:    "invalid " + check.Field,
+			public: true,
+		},
+		field: check.Field,
+	}
+} //runCheck()
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	Street string
+}
+
+func (addr testAddress) Validate() error {
+	if addr.Street == "" {
+		return Error("missing street")
+	}
+	return nil
+}
+
+type testUsername string
+
+var errTaken = Error("already taken")
+
+func (name testUsername) Validate(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Millisecond * 10): //pretend to look it up in a database
+	}
+	if name == "jan" {
+		return errTaken
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+
+	//all valid and nil pointers and funcs are skipped
+	var noAddress *testAddress
+	assert.Nil(t, Validate(ctx,
+		Check{"address", testAddress{Street: "44 Wide Street"}},
+		Check{"username", testUsername("piet")},
+		Check{"other", noAddress},
+		Check{"func", (func() error)(nil)},
+		Check{"func with context", (func(context.Context) error)(nil)},
+	))
+
+	//one failed
+	err := Validate(ctx,
+		Check{"address", testAddress{}},
+		Check{"username", testUsername("piet")},
+	)
+	assert.Equal(t, "invalid address because missing street", err.Error())
+	assert.Equal(t, "address", FieldPath(err))
+
+	//both failed, and nested field is reported with full path
+	err = Validate(ctx,
+		Check{"address", func() error { return WrapField(testAddress{}.Validate(), "home") }},
+		Check{"username", testUsername("jan")},
+	)
+	assert.Equal(t, "invalid address because invalid home because missing street; invalid username because already taken", err.Error())
+	assert.Equal(t, "address.home", FieldPath(err))
+	assert.True(t, errors.Is(err, errTaken))
+	assert.Equal(t, "- invalid address\n  invalid home\n  missing street\n- invalid username\n  already taken", fmt.Sprintf("%-s", err))
+
+	//not a validator
+	err = Validate(ctx, Check{"count", 5})
+	assert.Equal(t, "invalid count because int is not a validator", err.Error())
+}
+
+func TestValidateContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*5)
+	defer cancel()
+	err := Validate(ctx,
+		Check{"username", testUsername("jan")},
+		Check{"nickname", testUsername("piet")},
+	)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "invalid username because context deadline exceeded; validation not completed because context deadline exceeded", err.Error())
+}
+
+func TestValidateParallel(t *testing.T) {
+	running := int32(0)
+	maxRunning := int32(0)
+	check := func(name string) Check {
+		return Check{name, func(ctx context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			return testUsername(name).Validate(ctx)
+		}}
+	}
+	err := ValidateParallel(context.Background(), 2,
+		check("jan"), check("piet"), check("koos"), check("jan"),
+	)
+	assert.Equal(t, "invalid jan because already taken; invalid jan because already taken", err.Error())
+	assert.Equal(t, int32(2), maxRunning)
+}
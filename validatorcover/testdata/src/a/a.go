@@ -0,0 +1,106 @@
+package a
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-msvc/errors/v2"
+)
+
+type Address struct {
+	Street string
+}
+
+func (addr Address) Validate() error { return nil }
+
+type Username string
+
+func (name Username) Validate(ctx context.Context) error { return nil }
+
+type UpdateUserRequest struct {
+	Name     string
+	Address  *Address
+	Username Username
+	Billing  Address
+	Shipping Address
+	Previous []Address
+	Nicks    []Username // want `field Nicks of UpdateUserRequest has a Validate\(\) method but is not validated`
+}
+
+func (req UpdateUserRequest) Validate() error {
+	if req.Address != nil {
+		if err := req.Address.Validate(); err != nil {
+			return err
+		}
+	}
+	shipping := req.Shipping
+	for _, prev := range req.Previous {
+		if err := prev.Validate(); err != nil {
+			return err
+		}
+	}
+	return errors.Validate(context.Background(),
+		errors.Check{Field: "username", Value: req.Username},
+		errors.Check{"billing", req.Billing},
+		errors.Check{Field: "shipping", Value: shipping},
+		errors.Check{Field: "nicks", Value: count(req.Nicks)}, //a function that does not take a validator
+	)
+}
+
+func count(list []Username) error { return nil }
+
+type AddUserRequest struct {
+	Name     string
+	Home     Address            // want `field Home of AddUserRequest has a Validate\(\) method but is not validated in AddUserRequest.Validate\(\)`
+	Others   []*Address         // want `field Others of AddUserRequest has a Validate\(\) method but is not validated`
+	ByName   map[string]Address // want `field ByName of AddUserRequest has a Validate\(\) method but is not validated`
+	Work     Address            // want `field Work of AddUserRequest has a Validate\(\) method but is not validated`
+	Nicks    []Username
+	Previous *AddUserRequest //own type is not reported
+}
+
+func (req *AddUserRequest) Validate() error {
+	if req.Home.Street == "" { //using a field of Home does not validate it
+		return nil
+	}
+	if len(req.Others) == 0 || req.ByName != nil {
+		return nil
+	}
+	for _, other := range req.Others {
+		fmt.Println(other) //not validated
+	}
+	work := req.Work
+	fmt.Println(work, errors.Check{Field: "name", Value: req.Name})
+	for i := range req.Nicks {
+		if err := req.Nicks[i].Validate(context.Background()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// not a Validator method
+func (req AddUserRequest) Check() error { return nil }
+
+type Profile struct {
+	Address // want `field Address of Profile has a Validate\(\) method but is not validated in Profile.Validate\(\)`
+	Bio     string
+}
+
+func (p Profile) Validate() error {
+	if p.Street == "" {
+		return nil
+	}
+	return nil
+}
+
+type Account struct {
+	Address
+	Owner Address
+}
+
+func (a Account) Validate() error {
+	return check(a.Address, a.Owner)
+}
+
+func check(validators ...errors.Validator) error { return nil }
@@ -0,0 +1,15 @@
+// Package errors is a stub with the validation types of github.com/go-msvc/errors/v2 used in tests
+package errors
+
+import "context"
+
+type Validator interface {
+	Validate() error
+}
+
+type Check struct {
+	Field string
+	Value any
+}
+
+func Validate(ctx context.Context, checks ...Check) error { return nil }
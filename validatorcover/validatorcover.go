@@ -0,0 +1,303 @@
+// Package validatorcover defines an Analyzer that reports struct fields whose types
+// implement Validate() but are not validated in the Validate() method of the parent struct,
+// as the README of github.com/go-msvc/errors asks to call the nested Validate() from the parent.
+//
+// Fields are direct, pointer, slice, array or map element types with a method
+// Validate() error or Validate(context.Context) error.
+// Embedded fields are included, as the parent's Validate() hides theirs.
+// A field is validated when the parent's Validate() calls its Validate() method,
+// passes it to a param or struct field with a validator interface type, or to errors.Check{Value: ...},
+// or does one of these with a local variable that the field is assigned to, or with the values of a range over it.
+package validatorcover
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "validatorcover",
+	Doc:  "report fields with a Validate() method that are not validated in the parent's Validate() method",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			method, ok := decl.(*ast.FuncDecl)
+			if !ok || method.Recv == nil || method.Body == nil || method.Name.Name != "Validate" {
+				continue
+			}
+			fn, ok := pass.TypesInfo.Defs[method.Name].(*types.Func)
+			if !ok || !isValidateSignature(fn.Type().(*types.Signature)) {
+				continue
+			}
+			checkMethod(pass, method, fn)
+		}
+	}
+	return nil, nil
+}
+
+func checkMethod(pass *analysis.Pass, method *ast.FuncDecl, fn *types.Func) {
+	recv := fn.Type().(*types.Signature).Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	validated := validatedFields(pass, method.Body)
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if validated[f] {
+			continue
+		}
+		if elem := elemType(f.Type()); elem != nil && !types.Identical(elem, named) && hasValidate(elem) {
+			pass.Reportf(f.Pos(), "field %s of %s has a Validate() method but is not validated in %s.Validate()",
+				f.Name(), named.Obj().Name(), named.Obj().Name())
+		}
+	}
+} //checkMethod()
+
+// elemType returns the type that may be validated for a field of type t,
+// i.e. the element of pointers, slices, arrays and maps
+func elemType(t types.Type) types.Type {
+	for {
+		switch u := t.(type) {
+		case *types.Pointer:
+			t = u.Elem()
+			continue
+		case *types.Named, *types.Alias:
+			switch under := u.Underlying().(type) {
+			case *types.Slice, *types.Array, *types.Map:
+				//a named slice type may have its own Validate() method
+				if hasValidate(u) {
+					return u
+				}
+				t = under
+				continue
+			}
+			return u
+		case *types.Slice:
+			t = u.Elem()
+			continue
+		case *types.Array:
+			t = u.Elem()
+			continue
+		case *types.Map:
+			t = u.Elem()
+			continue
+		}
+		return nil
+	}
+}
+
+func hasValidate(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "Validate")
+	fn, ok := obj.(*types.Func)
+	return ok && isValidateSignature(fn.Type().(*types.Signature))
+}
+
+// isValidateSignature returns true for Validate() error and Validate(context.Context) error
+func isValidateSignature(sig *types.Signature) bool {
+	if sig.Results().Len() != 1 || sig.Results().At(0).Type().String() != "error" {
+		return false
+	}
+	switch sig.Params().Len() {
+	case 0:
+		return true
+	case 1:
+		return sig.Params().At(0).Type().String() == "context.Context"
+	}
+	return false
+}
+
+// validatedFields returns the fields that are used in body in a way that validates them,
+// directly or through local variables they are assigned to
+func validatedFields(pass *analysis.Pass, body *ast.BlockStmt) map[*types.Var]bool {
+	validated := map[*types.Var]bool{}     //fields and local variables
+	flows := map[*types.Var][]*types.Var{} //to the local variables they are assigned to
+	stack := []ast.Node{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		var v *types.Var
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if f, ok := pass.TypesInfo.Uses[n.Sel].(*types.Var); ok && f.IsField() {
+				v = f
+			}
+		case *ast.Ident:
+			if local, ok := pass.TypesInfo.Uses[n].(*types.Var); ok && !local.IsField() && body.Pos() <= local.Pos() && local.Pos() < body.End() {
+				v = local
+			}
+		}
+		if v != nil {
+			ok, to := validates(pass, n.(ast.Expr), stack)
+			if ok {
+				validated[v] = true
+			}
+			if to != nil {
+				flows[v] = append(flows[v], to)
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+	for changed := true; changed; {
+		changed = false
+		for v, locals := range flows {
+			for _, local := range locals {
+				if !validated[v] && validated[local] {
+					validated[v] = true
+					changed = true
+				}
+			}
+		}
+	}
+	return validated
+} //validatedFields()
+
+// validates returns true if the use of a field or variable in expr validates it, given its parents in stack,
+// i.e. its Validate() method is called or it is passed to a validator,
+// or else the local variable that it is assigned to, e.g. as the value of a range statement
+func validates(pass *analysis.Pass, expr ast.Expr, stack []ast.Node) (bool, *types.Var) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch parent := stack[i].(type) {
+		case *ast.ParenExpr, *ast.StarExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			if index, ok := parent.(*ast.IndexExpr); ok && index.X != expr {
+				return false, nil //e.g. the index in m[key]
+			}
+			expr = parent.(ast.Expr) //e.g. req.Items[i].Validate()
+			continue
+		case *ast.UnaryExpr:
+			if parent.Op == token.AND {
+				expr = parent
+				continue
+			}
+			return false, nil
+		case *ast.SelectorExpr:
+			return parent.X == expr && parent.Sel.Name == "Validate", nil
+		case *ast.RangeStmt:
+			if parent.X == expr && parent.Value != nil {
+				return false, local(pass, parent.Value)
+			}
+			return false, nil
+		case *ast.CallExpr:
+			return isValidator(paramType(pass, parent, expr)), nil
+		case *ast.KeyValueExpr:
+			if parent.Value != expr || i == 0 {
+				return false, nil
+			}
+			lit, ok := stack[i-1].(*ast.CompositeLit)
+			key, isIdent := parent.Key.(*ast.Ident)
+			return ok && isIdent && validatesStructField(pass, lit, key.Name), nil
+		case *ast.CompositeLit:
+			index := slices.Index(parent.Elts, expr)
+			if st, ok := structOf(pass, parent); ok && index >= 0 && index < st.NumFields() {
+				return validatesStructField(pass, parent, st.Field(index).Name()), nil
+			}
+			return false, nil
+		case *ast.AssignStmt:
+			for j, rhs := range parent.Rhs {
+				if rhs == expr && len(parent.Lhs) == len(parent.Rhs) {
+					return false, local(pass, parent.Lhs[j])
+				}
+			}
+			return false, nil
+		case *ast.ValueSpec:
+			for j, value := range parent.Values {
+				if value == expr && len(parent.Names) == len(parent.Values) {
+					return false, local(pass, parent.Names[j])
+				}
+			}
+			return false, nil
+		}
+		return false, nil
+	}
+	return false, nil
+} //validates()
+
+// local returns the local variable of expr, or nil
+func local(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	id, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, _ := pass.TypesInfo.ObjectOf(id).(*types.Var)
+	return v
+}
+
+// paramType returns the type of the param of the function called in call that arg is passed to, or nil
+func paramType(pass *analysis.Pass, call *ast.CallExpr, arg ast.Expr) types.Type {
+	sig, ok := pass.TypesInfo.TypeOf(call.Fun).Underlying().(*types.Signature)
+	if !ok {
+		return nil //builtin or conversion
+	}
+	i := slices.Index(call.Args, arg)
+	params := sig.Params()
+	switch {
+	case i < 0 || params.Len() == 0:
+		return nil
+	case sig.Variadic() && i >= params.Len()-1:
+		if call.Ellipsis.IsValid() {
+			return nil
+		}
+		return params.At(params.Len() - 1).Type().(*types.Slice).Elem()
+	case i < params.Len():
+		return params.At(i).Type()
+	}
+	return nil
+}
+
+// isValidator returns true when t is an interface with a Validate() method, like errors.Validator
+func isValidator(t types.Type) bool {
+	if t == nil || !types.IsInterface(t) {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "Validate")
+	fn, ok := obj.(*types.Func)
+	return ok && isValidateSignature(fn.Type().(*types.Signature))
+}
+
+// errorsPkgPath is the package with Check, whose Value is validated by errors.Validate()
+const errorsPkgPath = "github.com/go-msvc/errors/v2"
+
+// validatesStructField returns true when a value set in field of the composite literal is validated,
+// i.e. the Value of errors.Check, or a field with a validator interface type
+func validatesStructField(pass *analysis.Pass, lit *ast.CompositeLit, field string) bool {
+	st, ok := structOf(pass, lit)
+	if !ok {
+		return false
+	}
+	if named, ok := types.Unalias(pass.TypesInfo.TypeOf(lit)).(*types.Named); ok &&
+		named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == errorsPkgPath && named.Obj().Name() == "Check" {
+		return field == "Value"
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Name() == field {
+			return isValidator(st.Field(i).Type())
+		}
+	}
+	return false
+}
+
+func structOf(pass *analysis.Pass, lit *ast.CompositeLit) (*types.Struct, bool) {
+	t := pass.TypesInfo.TypeOf(lit)
+	if t == nil {
+		return nil, false
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	return st, ok
+}
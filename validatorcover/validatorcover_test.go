@@ -0,0 +1,12 @@
+package validatorcover_test
+
+import (
+	"testing"
+
+	"github.com/go-msvc/errors/validatorcover"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), validatorcover.Analyzer, "a")
+}